@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"gopher-cafe/internal/requestid"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	handler "gopher-cafe/internal/handler/grpc/coffeeshop"
+
+	cafepb "gopher-cafe/pkg/gen/go/cafe/v1"
+
+	"github.com/ajaibid/coin-common-golang/logger"
+	pb "github.com/rexyajaib/gopher-cafe/pkg/gen/go/v1"
+)
+
+// maxBodyBytes matches the default size a gRPC server accepts for a message.
+const maxBodyBytes = 4 << 20
+
+var gatewayMarshal = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// Gateway serves the brew and stats RPCs as JSON over HTTP, for the clients
+// that do not speak gRPC. The bodies are the gRPC messages in their JSON
+// form, and the requests go through the same interceptors as the RPCs.
+type Gateway struct {
+	coffee      *handler.CoffeeshopGrpcHandler
+	cafe        *handler.CafeGrpcHandler
+	interceptor grpc.UnaryServerInterceptor
+}
+
+func NewGateway(coffee *handler.CoffeeshopGrpcHandler, cafe *handler.CafeGrpcHandler, interceptors ...grpc.UnaryServerInterceptor) *Gateway {
+	return &Gateway{
+		coffee:      coffee,
+		cafe:        cafe,
+		interceptor: chainUnary(interceptors),
+	}
+}
+
+// Handler routes POST /v1/brew to CafeService.ExecuteBrew and GET /v1/stats
+// to GopherCafeService.GetStats.
+func (g *Gateway) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/brew", g.brew)
+	mux.HandleFunc("GET /v1/stats", g.stats)
+
+	return mux
+}
+
+func (g *Gateway) brew(w http.ResponseWriter, r *http.Request) {
+	// a body that cannot be read is still served through the interceptors, as
+	// an empty request, to get a request ID and an access log line
+	req := &cafepb.ExecuteBrewRequest{}
+	bodyErr := readBody(w, r, req)
+	if bodyErr != nil {
+		req.Reset()
+	}
+
+	g.serve(w, r, cafepb.CafeService_ExecuteBrew_FullMethodName, req, func(ctx context.Context, req any) (any, error) {
+		if bodyErr != nil {
+			return nil, status.Error(codes.InvalidArgument, bodyErr.Error())
+		}
+		return g.cafe.ExecuteBrew(ctx, req.(*cafepb.ExecuteBrewRequest))
+	})
+}
+
+func (g *Gateway) stats(w http.ResponseWriter, r *http.Request) {
+	g.serve(w, r, pb.GopherCafeService_GetStats_FullMethodName, &pb.GetStatsRequest{}, func(ctx context.Context, req any) (any, error) {
+		return g.coffee.GetStats(ctx, req.(*pb.GetStatsRequest))
+	})
+}
+
+// serve runs the handler of method through the interceptors, in the context
+// an RPC would have: the gateway headers as metadata and the client address
+// as peer.
+func (g *Gateway) serve(w http.ResponseWriter, r *http.Request, method string, req proto.Message, h grpc.UnaryHandler) {
+	id := r.Header.Get(requestid.MetadataKey)
+	if !requestid.Valid(id) {
+		id = requestid.New()
+	}
+	w.Header().Set(requestid.MetadataKey, id)
+
+	md := metadata.Pairs(requestid.MetadataKey, id)
+	if scheduler := r.Header.Get(handler.SchedulerMetadataKey); scheduler != "" {
+		md.Set(handler.SchedulerMetadataKey, scheduler)
+	}
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+	if addr, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: net.TCPAddrFromAddrPort(addr)})
+	}
+
+	resp, err := g.interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, h)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	body, err := gatewayMarshal.Marshal(resp.(proto.Message))
+	if err != nil {
+		writeError(w, status.Error(codes.Internal, err.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+func readBody(w http.ResponseWriter, r *http.Request, m proto.Message) error {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		return err
+	}
+
+	return protojson.Unmarshal(body, m)
+}
+
+// writeError writes the status of err as a google.rpc.Status, with the HTTP
+// status code matching its gRPC one.
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	body, mErr := gatewayMarshal.Marshal(st.Proto())
+	if mErr != nil {
+		logger.Errorf("Marshalling the status %s failed: %s", st, mErr)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(st.Code()))
+	_, _ = w.Write(body)
+}
+
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		// the client closed the request, as nginx has it
+		return 499
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// chainUnary composes interceptors the way grpc.ChainUnaryInterceptor does,
+// the first one being the outermost.
+func chainUnary(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+		next := h
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"gopher-cafe/internal/requestid"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"gopher-cafe/internal/entity/coffeeshop"
+	handler "gopher-cafe/internal/handler/grpc/coffeeshop"
+)
+
+// fakeUsecase brews every order in a single 10ms step and reports fixed
+// stats.
+type fakeUsecase struct {
+	handler.CoffeeshopUsecase
+}
+
+func (fakeUsecase) ExecuteBrew(_ context.Context, orders []coffeeshop.Order, _ int, _ coffeeshop.SchedulingStrategy) ([]coffeeshop.OrderResult, error) {
+	results := make([]coffeeshop.OrderResult, len(orders))
+	for i, o := range orders {
+		results[i] = coffeeshop.OrderResult{
+			OrderID: o.ID,
+			Status:  coffeeshop.OrderStatusCompleted,
+			Steps:   []coffeeshop.StepExecution{{Equipment: coffeeshop.EquipGrinder, EndTimeMs: 10}},
+		}
+	}
+
+	return results, nil
+}
+
+func (fakeUsecase) GetStats() (int64, int64, int64) {
+	return 7, 0, 42
+}
+
+func TestGateway(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string // a part of it
+		wantLog    string // a part of the access log line, none when empty
+	}{
+		{
+			name:       "brew",
+			method:     http.MethodPost,
+			path:       "/v1/brew",
+			body:       `{"baristas": 1, "orders": [{"id": 1, "drink": "DRINK_TYPE_ESPRESSO"}]}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"orderId":"1"`,
+			wantLog:    "method=/pkg.proto.cafe.v1.CafeService/ExecuteBrew code=OK",
+		},
+		{
+			name:       "stats",
+			method:     http.MethodGet,
+			path:       "/v1/stats",
+			wantStatus: http.StatusOK,
+			wantBody:   `"totalRequestProcessed":"7"`,
+			wantLog:    "code=OK",
+		},
+		{
+			name:       "invalid brew",
+			method:     http.MethodPost,
+			path:       "/v1/brew",
+			body:       `{"baristas": 0, "orders": []}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `"code":3`,
+			wantLog:    "code=InvalidArgument",
+		},
+		{
+			name:       "malformed body",
+			method:     http.MethodPost,
+			path:       "/v1/brew",
+			body:       `{"baristas":`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `"code":3`,
+			wantLog:    "method=/pkg.proto.cafe.v1.CafeService/ExecuteBrew code=InvalidArgument",
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			path:       "/v1/brew",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "unknown path",
+			method:     http.MethodGet,
+			path:       "/v1/menu",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := catchAccessLog(t)
+			gateway := NewGateway(handler.NewCoffeeshopGrpcHandler(fakeUsecase{}), handler.NewCafeGrpcHandler(fakeUsecase{}),
+				RequestIDMiddleware(), AccessLogMiddleware(), RecoveryMiddleware())
+
+			rec := httptest.NewRecorder()
+			gateway.Handler().ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, strings.ReplaceAll(rec.Body.String(), " ", ""), tt.wantBody)
+			if tt.wantLog == "" {
+				assert.Empty(t, *lines)
+				return
+			}
+			id := rec.Header().Get(requestid.MetadataKey)
+			assert.True(t, requestid.Valid(id))
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.True(t, json.Valid(rec.Body.Bytes()))
+			if assert.Len(t, *lines, 1) {
+				assert.Contains(t, (*lines)[0], "request_id="+id)
+				assert.Contains(t, (*lines)[0], tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want int
+	}{
+		{codes.OK, http.StatusOK},
+		{codes.InvalidArgument, http.StatusBadRequest},
+		{codes.FailedPrecondition, http.StatusBadRequest},
+		{codes.OutOfRange, http.StatusBadRequest},
+		{codes.Unauthenticated, http.StatusUnauthorized},
+		{codes.PermissionDenied, http.StatusForbidden},
+		{codes.NotFound, http.StatusNotFound},
+		{codes.AlreadyExists, http.StatusConflict},
+		{codes.Aborted, http.StatusConflict},
+		{codes.ResourceExhausted, http.StatusTooManyRequests},
+		{codes.Canceled, 499},
+		{codes.Unimplemented, http.StatusNotImplemented},
+		{codes.Unavailable, http.StatusServiceUnavailable},
+		{codes.DeadlineExceeded, http.StatusGatewayTimeout},
+		{codes.Internal, http.StatusInternalServerError},
+		{codes.Unknown, http.StatusInternalServerError},
+		{codes.DataLoss, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, httpStatus(tt.code))
+		})
+	}
+}
+
+func TestChainUnary(t *testing.T) {
+	var calls []string
+	interceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+			calls = append(calls, name+" in "+info.FullMethod)
+			resp, err := h(ctx, req)
+			calls = append(calls, name+" out")
+			return resp, err
+		}
+	}
+
+	chain := chainUnary([]grpc.UnaryServerInterceptor{interceptor("first"), interceptor("second")})
+	resp, err := chain(t.Context(), "req", &grpc.UnaryServerInfo{FullMethod: "/m"}, func(_ context.Context, req any) (any, error) {
+		calls = append(calls, "handler")
+		return req.(string) + " served", nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "req served", resp)
+	assert.Equal(t, []string{"first in /m", "second in /m", "handler", "second out", "first out"}, calls)
+}
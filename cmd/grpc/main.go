@@ -1,15 +1,24 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"gopher-cafe/internal/admission"
+	"gopher-cafe/internal/clock"
 	"gopher-cafe/internal/entity/coffeeshop"
+	"gopher-cafe/internal/menu"
 	"gopher-cafe/internal/worker"
 	"log"
+	"maps"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"runtime/debug"
 	"strconv"
+	"sync"
 	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -18,6 +27,8 @@ import (
 	handler "gopher-cafe/internal/handler/grpc/coffeeshop"
 	usecase "gopher-cafe/internal/usecase/coffeeshop"
 
+	cafepb "gopher-cafe/pkg/gen/go/cafe/v1"
+
 	pb "github.com/rexyajaib/gopher-cafe/pkg/gen/go/v1"
 
 	"github.com/ajaibid/coin-common-golang/config"
@@ -28,15 +39,42 @@ import (
 
 func main() {
 	var (
+		cfg              appCfg.Config
 		equipPoolManager *worker.EquipPoolManager
 		grpcServer       *grpc.Server
+		gatewayServer    *http.Server
+		metricsServer    *http.Server
+		coffeeUsecase    *usecase.CoffeeshopUsecase
 	)
 
+	// the equipment is only stopped once the brews in flight are drained
 	shutdown := func() {
 		logger.Info("Begin Shutting down gracefully...")
+		var wg sync.WaitGroup
 		if grpcServer != nil {
 			logger.Info("Shutting down grpc server...")
-			grpcServer.Stop()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				drain(grpcServer, cfg.Grpc.DrainTimeout)
+			}()
+		}
+		if gatewayServer != nil {
+			logger.Info("Shutting down http gateway...")
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				drainHTTP(gatewayServer, cfg.Grpc.DrainTimeout)
+			}()
+		}
+		wg.Wait()
+		if metricsServer != nil {
+			logger.Info("Shutting down metrics server...")
+			drainHTTP(metricsServer, cfg.Grpc.DrainTimeout)
+		}
+		if coffeeUsecase != nil {
+			logger.Info("Draining tickets...")
+			coffeeUsecase.DrainTickets(cfg.Grpc.DrainTimeout)
 		}
 		if equipPoolManager != nil {
 			logger.Info("Shutting down manager...")
@@ -52,8 +90,10 @@ func main() {
 		}
 	}()
 
+	sigCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
 	// Create a TCP Listener on a specific port
-	var cfg appCfg.Config
 	err := config.LoadConfig(&cfg, "config/.env")
 	if err != nil {
 		log.Fatal(err)
@@ -67,42 +107,174 @@ func main() {
 		LogFormatter: cfg.Logger.LogFormatter,
 	})
 
-	equipWorkers := worker.EquipmentWorkers
+	cafeMenu, err := menu.Load(cfg.Menu.File)
+	if err != nil {
+		log.Fatalf("failed to load menu: %v", err)
+	}
+	logger.Infof("Menu loaded from %s: %v", cfg.Menu.File, cafeMenu.Drinks())
+
+	// the menu file overrides the default number of workers of an equipment
+	equipWorkers := maps.Clone(worker.EquipmentWorkers)
+	maps.Copy(equipWorkers, cafeMenu.Workers())
 
-	equipPoolManager = worker.NewEquipPoolManager(uint8(len(equipWorkers)))
+	equipPoolManager = worker.NewEquipPoolManager(uint8(len(equipWorkers)), clock.New())
 	for k, v := range equipWorkers {
 		equipPoolManager.Register(k, v)
 	}
+	if err := cafeMenu.Validate(equipPoolManager.Workers()); err != nil {
+		log.Fatalf("invalid menu: %v", err)
+	}
+	for k, v := range cafeMenu.Faults() {
+		if err := equipPoolManager.SetFaults(k, v); err != nil {
+			log.Fatalf("invalid menu: %s: %v", k, err)
+		}
+	}
 	equipPoolManager.StartAll()
 
 	metrics := coffeeshop.NewOrderMetrics()
 
 	// Initialize the Layers
-	coffeeUsecase := usecase.NewCoffeeshopUsecase(equipPoolManager, metrics)
+	usecaseOpts := []usecase.Option{
+		usecase.WithScheduling(coffeeshop.SchedulingStrategy(cfg.Brew.Scheduler)),
+	}
+	if cfg.Brew.Simulation {
+		usecaseOpts = append(usecaseOpts, usecase.WithSimulation())
+	}
+	coffeeUsecase = usecase.NewCoffeeshopUsecase(equipPoolManager, cafeMenu, metrics, usecaseOpts...)
 	coffeeHandler := handler.NewCoffeeshopGrpcHandler(coffeeUsecase)
 
+	// Apply the changes of the menu file without a restart
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	err = menu.Watch(watchCtx, cfg.Menu.File, func(m *menu.Menu) {
+		if err := coffeeUsecase.ApplyMenu(m); err != nil {
+			logger.Errorf("Applying the reloaded menu failed, keeping the previous one: %s", err)
+		}
+	})
+	if err != nil {
+		logger.Errorf("Menu changes will need a restart: %s", err)
+	}
+
+	// Expose the metrics for Prometheus to scrape
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", MetricsHandler(metrics, coffeeUsecase))
+	metricsServer = &http.Server{
+		Addr:    ":" + strconv.Itoa(cfg.Metrics.Port),
+		Handler: metricsMux,
+	}
+	go func() {
+		log.Printf("Metrics are served on %v", metricsServer.Addr)
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("failed to serve metrics: %v", err)
+		}
+	}()
+
 	// Create the gRPC Server instance
-	grpcServer = grpc.NewServer(grpc.UnaryInterceptor(TimeoutMiddleware()))
+	methodTimeouts, err := cfg.Grpc.Timeouts()
+	if err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+	admissionCtrl := admission.New(admission.Limits{
+		Rate:        cfg.Admission.RateLimit,
+		Burst:       cfg.Admission.RateBurst,
+		MaxInFlight: cfg.Admission.MaxInFlight,
+		MaxOrders:   cfg.Admission.MaxOrders,
+		MaxBaristas: cfg.Admission.MaxBaristas,
+	}, clock.New())
+
+	// the request ID comes first so that every line logged for a request
+	// carries it, and panics are recovered within the access log so that
+	// their RPCs are logged as Internal. Rejected requests are logged too.
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		RequestIDMiddleware(),
+		AccessLogMiddleware(),
+		RecoveryMiddleware(),
+		AdmissionMiddleware(admissionCtrl),
+		TimeoutMiddleware(cfg.Grpc.Timeout, methodTimeouts),
+	}
+	grpcServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(
+			StreamRequestIDMiddleware(),
+			StreamAccessLogMiddleware(),
+			StreamRecoveryMiddleware(),
+			StreamAdmissionMiddleware(admissionCtrl),
+			StreamTimeoutMiddleware(methodTimeouts),
+		),
+	)
 
 	// Register the Service (The "Route Definition")
 	// This tells the gRPC server to route incoming GopherCafe calls to our handler.
+	cafeHandler := handler.NewCafeGrpcHandler(coffeeUsecase)
 	pb.RegisterGopherCafeServiceServer(grpcServer, coffeeHandler)
+	cafepb.RegisterCafeServiceServer(grpcServer, cafeHandler)
+	cafepb.RegisterCafeAdminServiceServer(grpcServer, handler.NewAdminGrpcHandler(coffeeUsecase))
 
 	// Optional: Enable reflection.
 	// This allows tools like Postman or 'evans' to "see" your endpoints automatically.
 	reflection.Register(grpcServer)
 
 	// Start Serving
-	log.Printf("Coffee Shop Simulation Server is running on %v", lis.Addr())
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	serveErr := make(chan error, 2)
+	go func() {
+		log.Printf("Coffee Shop Simulation Server is running on %v", lis.Addr())
+		serveErr <- grpcServer.Serve(lis)
+	}()
+
+	// Serve the JSON gateway of the same handlers and interceptors
+	gatewayServer = &http.Server{
+		Addr:    ":" + strconv.Itoa(cfg.Http.Port),
+		Handler: NewGateway(coffeeHandler, cafeHandler, unaryInterceptors...).Handler(),
+	}
+	go func() {
+		log.Printf("HTTP gateway is running on %v", gatewayServer.Addr)
+		if err := gatewayServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+
+	select {
+	case <-sigCtx.Done():
+		// a second signal kills the server without waiting for the drain
+		stopSignals()
+		logger.Info("Received shutdown signal, shutting down...")
+		shutdown()
+	case err := <-serveErr:
+		logger.Errorf("failed to serve: %v", err)
+		shutdown()
+		os.Exit(1)
 	}
+}
+
+// drain stops the server from taking new RPCs and waits up to timeout for the
+// ones in flight to finish, cancelling them past it.
+func drain(server *grpc.Server, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
 
-	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
 
-	<-sig
+	select {
+	case <-done:
+	case <-timer.C:
+		logger.Errorf("Requests in flight did not finish within %s, cancelling them", timeout)
+		server.Stop()
+		<-done
+	}
+}
 
-	logger.Info("Received shutdown signal, shutting down...")
-	shutdown()
+// drainHTTP stops the server from taking new requests and waits up to timeout
+// for the ones in flight to finish, closing their connections past it.
+func drainHTTP(server *http.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); errors.Is(err, context.DeadlineExceeded) {
+		logger.Errorf("HTTP requests in flight did not finish within %s, closing them", timeout)
+		_ = server.Close()
+	}
 }
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	cafepb "gopher-cafe/pkg/gen/go/cafe/v1"
+)
+
+// slowCafe answers ExecuteBrew after brewTime, signalling started once the
+// request is in flight.
+type slowCafe struct {
+	cafepb.UnimplementedCafeServiceServer
+	brewTime time.Duration
+	started  chan struct{}
+}
+
+func (s *slowCafe) ExecuteBrew(ctx context.Context, _ *cafepb.ExecuteBrewRequest) (*cafepb.ExecuteBrewResponse, error) {
+	close(s.started)
+	select {
+	case <-time.After(s.brewTime):
+		return &cafepb.ExecuteBrewResponse{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+var drainTests = []struct {
+	name     string
+	brewTime time.Duration
+	timeout  time.Duration
+	wantErr  bool
+}{
+	{
+		name:     "finishes within the timeout",
+		brewTime: 50 * time.Millisecond,
+		timeout:  5 * time.Second,
+	},
+	{
+		name:     "cancelled past the timeout",
+		brewTime: time.Minute,
+		timeout:  50 * time.Millisecond,
+		wantErr:  true,
+	},
+}
+
+func TestDrain(t *testing.T) {
+	for _, tt := range drainTests {
+		t.Run(tt.name, func(t *testing.T) {
+			lis := bufconn.Listen(1 << 20)
+			server := grpc.NewServer()
+			cafe := &slowCafe{brewTime: tt.brewTime, started: make(chan struct{})}
+			cafepb.RegisterCafeServiceServer(server, cafe)
+			go func() { _ = server.Serve(lis) }()
+
+			conn, err := grpc.NewClient("passthrough:///bufnet",
+				grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+				grpc.WithTransportCredentials(insecure.NewCredentials()),
+			)
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			errc := make(chan error, 1)
+			go func() {
+				_, err := cafepb.NewCafeServiceClient(conn).ExecuteBrew(context.Background(), &cafepb.ExecuteBrewRequest{})
+				errc <- err
+			}()
+			<-cafe.started
+
+			start := time.Now()
+			drain(server, tt.timeout)
+
+			assert.Less(t, time.Since(start), tt.timeout+time.Second)
+			if tt.wantErr {
+				assert.Error(t, <-errc)
+			} else {
+				assert.NoError(t, <-errc)
+			}
+		})
+	}
+}
+
+func TestDrainHTTP(t *testing.T) {
+	for _, tt := range drainTests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan struct{})
+			server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				select {
+				case <-time.After(tt.brewTime):
+				case <-r.Context().Done():
+				}
+			})}
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			assert.NoError(t, err)
+			go func() { _ = server.Serve(lis) }()
+
+			errc := make(chan error, 1)
+			go func() {
+				resp, err := http.Get("http://" + lis.Addr().String())
+				if err == nil {
+					resp.Body.Close()
+				}
+				errc <- err
+			}()
+			<-started
+
+			start := time.Now()
+			drainHTTP(server, tt.timeout)
+
+			assert.Less(t, time.Since(start), tt.timeout+time.Second)
+			if tt.wantErr {
+				assert.Error(t, <-errc)
+			} else {
+				assert.NoError(t, <-errc)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"gopher-cafe/internal/entity/coffeeshop"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type EquipmentStatsGetter interface {
+	GetEquipmentStats() map[coffeeshop.EquipmentType]coffeeshop.EquipmentStats
+}
+
+// MetricsHandler serves the order and equipment metrics in the Prometheus text
+// exposition format.
+func MetricsHandler(metrics *coffeeshop.OrderMetrics, equipment EquipmentStatsGetter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+
+		snap := metrics.Snapshot()
+
+		writeHeader(&buf, "gophercafe_requests_total", "counter", "Brew requests fully processed.")
+		fmt.Fprintf(&buf, "gophercafe_requests_total %d\n", snap.TotalRequests)
+
+		writeHeader(&buf, "gophercafe_orders_total", "counter", "Orders brewed.")
+		fmt.Fprintf(&buf, "gophercafe_orders_total %d\n", snap.TotalOrders)
+
+		writeHeader(&buf, "gophercafe_orders_due_total", "counter", "Orders with a due time that completed or failed.")
+		fmt.Fprintf(&buf, "gophercafe_orders_due_total %d\n", snap.DueOrders)
+
+		writeHeader(&buf, "gophercafe_orders_on_time_total", "counter", "Orders that completed by their due time.")
+		fmt.Fprintf(&buf, "gophercafe_orders_on_time_total %d\n", snap.OnTimeOrders)
+
+		writeHeader(&buf, "gophercafe_orders_on_time_percent", "gauge", "Share of the orders with a due time that met it.")
+		fmt.Fprintf(&buf, "gophercafe_orders_on_time_percent %s\n", strconv.FormatFloat(snap.OnTimePercent(), 'f', -1, 64))
+
+		writeHeader(&buf, "gophercafe_order_duration_milliseconds", "histogram", "Time from the first step start to the last step end of an order.")
+		writeHistogram(&buf, "gophercafe_order_duration_milliseconds", "", snap.Duration)
+
+		writeHeader(&buf, "gophercafe_drink_duration_milliseconds", "histogram", "Order duration by drink.")
+		drinks := slices.SortedFunc(maps.Keys(snap.DrinkDuration), func(a, b coffeeshop.DrinkType) int {
+			return cmp.Compare(a.String(), b.String())
+		})
+		for _, drink := range drinks {
+			writeHistogram(&buf, "gophercafe_drink_duration_milliseconds", label("drink", drink.String()), snap.DrinkDuration[drink])
+		}
+
+		writeHeader(&buf, "gophercafe_priority_duration_milliseconds", "histogram", "Order duration by priority class.")
+		priorities := slices.SortedFunc(maps.Keys(snap.PriorityDuration), func(a, b coffeeshop.Priority) int {
+			return cmp.Compare(a.Rank(), b.Rank())
+		})
+		for _, priority := range priorities {
+			writeHistogram(&buf, "gophercafe_priority_duration_milliseconds", label("priority", priority.String()), snap.PriorityDuration[priority])
+		}
+
+		writeHeader(&buf, "gophercafe_order_transitions_total", "counter", "Orders that went to a state of their lifecycle.")
+		for _, state := range slices.Sorted(maps.Keys(snap.Transitions)) {
+			fmt.Fprintf(&buf, "gophercafe_order_transitions_total{%s} %d\n", label("state", state.String()), snap.Transitions[state])
+		}
+
+		stats := equipment.GetEquipmentStats()
+		equipments := slices.Sorted(maps.Keys(stats))
+
+		writeHeader(&buf, "gophercafe_equipment_workers", "gauge", "Workers of an equipment pool.")
+		for _, equip := range equipments {
+			fmt.Fprintf(&buf, "gophercafe_equipment_workers{%s} %d\n", label("equipment", equip.String()), stats[equip].Workers)
+		}
+
+		writeHeader(&buf, "gophercafe_equipment_busy_workers", "gauge", "Workers of an equipment pool processing a step.")
+		for _, equip := range equipments {
+			fmt.Fprintf(&buf, "gophercafe_equipment_busy_workers{%s} %d\n", label("equipment", equip.String()), stats[equip].BusyWorkers)
+		}
+
+		writeHeader(&buf, "gophercafe_equipment_queue_depth", "gauge", "Steps waiting for a free worker of an equipment pool.")
+		for _, equip := range equipments {
+			fmt.Fprintf(&buf, "gophercafe_equipment_queue_depth{%s} %d\n", label("equipment", equip.String()), stats[equip].QueueDepth)
+		}
+
+		writeHeader(&buf, "gophercafe_equipment_jobs_completed_total", "counter", "Steps completed by an equipment pool.")
+		for _, equip := range equipments {
+			fmt.Fprintf(&buf, "gophercafe_equipment_jobs_completed_total{%s} %d\n", label("equipment", equip.String()), stats[equip].JobsCompleted)
+		}
+
+		writeHeader(&buf, "gophercafe_equipment_wait_milliseconds_total", "counter", "Time steps spent waiting for a free worker of an equipment pool.")
+		for _, equip := range equipments {
+			fmt.Fprintf(&buf, "gophercafe_equipment_wait_milliseconds_total{%s} %d\n", label("equipment", equip.String()), stats[equip].WaitTime.Milliseconds())
+		}
+
+		writeHeader(&buf, "gophercafe_equipment_processing_milliseconds_total", "counter", "Time workers of an equipment pool spent processing steps.")
+		for _, equip := range equipments {
+			fmt.Fprintf(&buf, "gophercafe_equipment_processing_milliseconds_total{%s} %d\n", label("equipment", equip.String()), stats[equip].ProcessingTime.Milliseconds())
+		}
+
+		writeHeader(&buf, "gophercafe_equipment_worker_busy_milliseconds_total", "counter", "Time a single worker spent processing steps.")
+		for _, equip := range equipments {
+			for id, busy := range stats[equip].WorkerBusyTime {
+				fmt.Fprintf(&buf, "gophercafe_equipment_worker_busy_milliseconds_total{%s,%s} %d\n", label("equipment", equip.String()), label("worker", strconv.Itoa(id)), busy.Milliseconds())
+			}
+		}
+
+		writeHeader(&buf, "gophercafe_equipment_utilization_ratio", "gauge", "Share of the worker time of an equipment pool spent processing steps.")
+		for _, equip := range equipments {
+			fmt.Fprintf(&buf, "gophercafe_equipment_utilization_ratio{%s} %s\n", label("equipment", equip.String()), strconv.FormatFloat(stats[equip].Utilization, 'f', -1, 64))
+		}
+
+		writeHeader(&buf, "gophercafe_equipment_down_workers", "gauge", "Workers of an equipment pool being repaired or cleaned.")
+		for _, equip := range equipments {
+			fmt.Fprintf(&buf, "gophercafe_equipment_down_workers{%s,%s} %d\n", label("equipment", equip.String()), label("reason", "repair"), stats[equip].Broken)
+			fmt.Fprintf(&buf, "gophercafe_equipment_down_workers{%s,%s} %d\n", label("equipment", equip.String()), label("reason", "cleaning"), stats[equip].Cleaning)
+		}
+
+		writeHeader(&buf, "gophercafe_equipment_outages_total", "counter", "Breakdowns and cleanings of the workers of an equipment pool.")
+		for _, equip := range equipments {
+			fmt.Fprintf(&buf, "gophercafe_equipment_outages_total{%s,%s} %d\n", label("equipment", equip.String()), label("reason", "repair"), stats[equip].Breakdowns)
+			fmt.Fprintf(&buf, "gophercafe_equipment_outages_total{%s,%s} %d\n", label("equipment", equip.String()), label("reason", "cleaning"), stats[equip].Cleanings)
+		}
+
+		writeHeader(&buf, "gophercafe_equipment_downtime_milliseconds_total", "counter", "Time workers of an equipment pool spent being repaired or cleaned.")
+		for _, equip := range equipments {
+			fmt.Fprintf(&buf, "gophercafe_equipment_downtime_milliseconds_total{%s,%s} %d\n", label("equipment", equip.String()), label("reason", "repair"), stats[equip].RepairTime.Milliseconds())
+			fmt.Fprintf(&buf, "gophercafe_equipment_downtime_milliseconds_total{%s,%s} %d\n", label("equipment", equip.String()), label("reason", "cleaning"), stats[equip].CleaningTime.Milliseconds())
+		}
+
+		w.Header().Set("Content-Type", metricsContentType)
+		_, _ = w.Write(buf.Bytes())
+	})
+}
+
+func writeHeader(buf *bytes.Buffer, name, kind, help string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, kind)
+}
+
+func writeHistogram(buf *bytes.Buffer, name, labels string, h coffeeshop.DurationHistogram) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+
+	for i, bound := range coffeeshop.DurationBucketsMs {
+		fmt.Fprintf(buf, "%s_bucket{%s%sle=\"%d\"} %d\n", name, labels, sep, bound, h.Buckets[i])
+	}
+	fmt.Fprintf(buf, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, h.Count)
+
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(buf, "%s_sum%s %d\n", name, labels, h.SumMs)
+	fmt.Fprintf(buf, "%s_count%s %d\n", name, labels, h.Count)
+}
+
+// labelEscaper escapes a label value the way the text format does, which
+// unlike Go quoting leaves every other character as it is.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func label(name, value string) string {
+	return name + `="` + labelEscaper.Replace(value) + `"`
+}
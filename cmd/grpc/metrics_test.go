@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gopher-cafe/internal/entity/coffeeshop"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+type fakeEquipment map[coffeeshop.EquipmentType]coffeeshop.EquipmentStats
+
+func (f fakeEquipment) GetEquipmentStats() map[coffeeshop.EquipmentType]coffeeshop.EquipmentStats {
+	return f
+}
+
+func TestMetricsHandler(t *testing.T) {
+	metrics := coffeeshop.NewOrderMetrics()
+	metrics.RecordTotalRequests(1)
+
+	// 3ms falls in every bucket, 30ms from the 50ms one and 7s only in +Inf
+	for _, res := range []coffeeshop.OrderResult{
+		{OrderID: 1, Drink: coffeeshop.DrinkEspresso, Steps: []coffeeshop.StepExecution{{StartTimeMs: 0, EndTimeMs: 3}}},
+		{OrderID: 2, Drink: coffeeshop.DrinkEspresso, Priority: coffeeshop.PriorityVIP, Steps: []coffeeshop.StepExecution{{StartTimeMs: 10, EndTimeMs: 25}, {StartTimeMs: 0, EndTimeMs: 30}}},
+		{OrderID: 3, Drink: coffeeshop.DrinkType("cold \"brew\"\\\n"), Steps: []coffeeshop.StepExecution{{StartTimeMs: 0, EndTimeMs: 7000}}},
+	} {
+		metrics.RecordOrder(res)
+	}
+	metrics.RecordDeadline(coffeeshop.OrderResult{DueAtMs: 10, LatenessMs: -5, Status: coffeeshop.OrderStatusCompleted})
+	metrics.RecordTransition(coffeeshop.Transition{To: coffeeshop.OrderStateReady})
+
+	equipment := fakeEquipment{
+		coffeeshop.EquipGrinder: {
+			Workers:        2,
+			BusyWorkers:    1,
+			QueueDepth:     3,
+			JobsCompleted:  4,
+			WaitTime:       5 * time.Millisecond,
+			ProcessingTime: 30 * time.Millisecond,
+			WorkerBusyTime: []time.Duration{20 * time.Millisecond, 10 * time.Millisecond},
+			Utilization:    0.25,
+			Cleaning:       1,
+			Cleanings:      2,
+			CleaningTime:   40 * time.Millisecond,
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	MetricsHandler(metrics, equipment).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, metricsContentType, rec.Header().Get("Content-Type"))
+
+	golden := filepath.Join("testdata", "metrics.golden")
+	if *update {
+		assert.NoError(t, os.WriteFile(golden, rec.Body.Bytes(), 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), rec.Body.String())
+}
@@ -2,15 +2,279 @@ package main
 
 import (
 	"context"
+	"gopher-cafe/internal/admission"
+	"gopher-cafe/internal/requestid"
+	"net"
+	"path"
+	"runtime/debug"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	cafepb "gopher-cafe/pkg/gen/go/cafe/v1"
+
+	"github.com/ajaibid/coin-common-golang/logger"
+	pb "github.com/rexyajaib/gopher-cafe/pkg/gen/go/v1"
 )
 
-func TimeoutMiddleware() grpc.UnaryServerInterceptor {
+// RequestIDMiddleware tags a unary RPC with the request ID sent by the client,
+// or a new one, and sends it back in the response header.
+func RequestIDMiddleware() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		return handler(withRequestID(ctx), req)
+	}
+}
+
+// StreamRequestIDMiddleware is RequestIDMiddleware for streaming RPCs.
+func StreamRequestIDMiddleware() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+	}
+}
+
+// withRequestID returns a copy of ctx that carries the request ID of the
+// incoming metadata. IDs that are missing or unsafe to log are replaced with
+// new ones.
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestid.MetadataKey); len(values) > 0 && requestid.Valid(values[0]) {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = requestid.New()
+	}
+
+	// the requests of the gateway have no stream, it sends the ID itself
+	if grpc.ServerTransportStreamFromContext(ctx) != nil {
+		if err := grpc.SetHeader(ctx, metadata.Pairs(requestid.MetadataKey, id)); err != nil {
+			logger.Errorf("request_id=%s sending the request ID failed: %s", id, err)
+		}
+	}
+
+	return requestid.NewContext(ctx, id)
+}
+
+// AccessLogMiddleware logs a line per unary RPC once it is served.
+func AccessLogMiddleware() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
-		timeout, cancelFunc := context.WithTimeout(ctx, time.Second*2)
+		start := time.Now()
+		resp, err = handler(ctx, req)
+		orders, _ := brewSize(req)
+		logAccess(ctx, info.FullMethod, err, time.Since(start), orders)
+
+		return resp, err
+	}
+}
+
+// StreamAccessLogMiddleware logs a line per streaming RPC once it is served,
+// counting the orders of every message received.
+func StreamAccessLogMiddleware() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		counted := &countingStream{ServerStream: ss}
+		err := handler(srv, counted)
+		logAccess(ss.Context(), info.FullMethod, err, time.Since(start), counted.orders)
+
+		return err
+	}
+}
+
+// accessLogf writes the access log lines, through the logger unless a test
+// catches them.
+var accessLogf = logger.Infof
+
+func logAccess(ctx context.Context, method string, err error, latency time.Duration, orders int) {
+	accessLogf("request_id=%s method=%s code=%s latency_ms=%d orders=%d",
+		requestid.FromContext(ctx), method, status.Code(err), latency.Milliseconds(), orders)
+}
+
+// brewSize is the number of orders and baristas of a brew request, zero for
+// the other requests.
+func brewSize(req any) (orders, baristas int) {
+	switch r := req.(type) {
+	case *pb.ExecuteBrewRequest:
+		return len(r.GetOrders()), int(r.GetBaristas())
+	case *cafepb.ExecuteBrewRequest:
+		return len(r.GetOrders()), int(r.GetBaristas())
+	default:
+		return 0, 0
+	}
+}
+
+// countingStream counts the orders of the messages received on a stream.
+type countingStream struct {
+	grpc.ServerStream
+	orders int
+}
+
+func (s *countingStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		orders, _ := brewSize(m)
+		s.orders += orders
+	}
+
+	return err
+}
+
+// RecoveryMiddleware turns a panic of a unary handler into an Internal error
+// rather than letting it take the server down.
+func RecoveryMiddleware() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				resp, err = nil, recovered(ctx, info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecoveryMiddleware is RecoveryMiddleware for streaming RPCs.
+func StreamRecoveryMiddleware() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func recovered(ctx context.Context, method string, r any) error {
+	logger.Errorf("request_id=%s method=%s recovered from panic: %v, stack trace: %s",
+		requestid.FromContext(ctx), method, r, debug.Stack())
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+// brewMethods are the RPCs that brew, by method name.
+var brewMethods = map[string]bool{
+	"ExecuteBrew":  true,
+	"StreamBrew":   true,
+	"SubmitOrders": true,
+}
+
+// AdmissionMiddleware rejects the unary brew requests beyond the limits of
+// ctrl with ResourceExhausted. A request holds its slot until it is served,
+// or until its brew is done when the handler takes it over with
+// admission.Hold.
+func AdmissionMiddleware(ctrl *admission.Controller) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		if !brewMethods[path.Base(info.FullMethod)] {
+			return handler(ctx, req)
+		}
+
+		if err := ctrl.Check(brewSize(req)); err != nil {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		release, err := ctrl.Admit(clientOf(ctx))
+		if err != nil {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		ctx, done := admission.NewContext(ctx, release)
+		defer done()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAdmissionMiddleware is AdmissionMiddleware for streaming RPCs, whose
+// request is checked once it is received.
+func StreamAdmissionMiddleware(ctrl *admission.Controller) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !brewMethods[path.Base(info.FullMethod)] {
+			return handler(srv, ss)
+		}
+
+		release, err := ctrl.Admit(clientOf(ss.Context()))
+		if err != nil {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		defer release()
+
+		return handler(srv, &admittingStream{ServerStream: ss, ctrl: ctrl})
+	}
+}
+
+// admittingStream checks the size of the messages received on a stream.
+type admittingStream struct {
+	grpc.ServerStream
+	ctrl *admission.Controller
+}
+
+func (s *admittingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if err := s.ctrl.Check(brewSize(m)); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	return nil
+}
+
+// clientOf tells the clients apart by the host of their address.
+func clientOf(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// TimeoutMiddleware bounds a unary RPC with the timeout of its method, by
+// method name, or with timeout otherwise. A client deadline that comes
+// earlier is kept.
+func TimeoutMiddleware(timeout time.Duration, methods map[string]time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		t, ok := methods[path.Base(info.FullMethod)]
+		if !ok {
+			t = timeout
+		}
+
+		ctx, cancelFunc := context.WithTimeout(ctx, t)
+		defer cancelFunc()
+		return handler(ctx, req)
+	}
+}
+
+// StreamTimeoutMiddleware bounds a streaming RPC with the timeout of its
+// method, if it has one, as streams are not bounded by default.
+func StreamTimeoutMiddleware(methods map[string]time.Duration) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		timeout, ok := methods[path.Base(info.FullMethod)]
+		if !ok {
+			return handler(srv, ss)
+		}
+
+		ctx, cancelFunc := context.WithTimeout(ss.Context(), timeout)
 		defer cancelFunc()
-		return handler(timeout, req)
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
 	}
 }
+
+// contextStream is a server stream with a context of its own.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gopher-cafe/internal/admission"
+	"gopher-cafe/internal/clock"
+	"gopher-cafe/internal/requestid"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	cafepb "gopher-cafe/pkg/gen/go/cafe/v1"
+)
+
+// fakeServerStream is a server stream that receives msg.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	msg proto.Message
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) RecvMsg(m any) error {
+	proto.Merge(m.(proto.Message), s.msg)
+	return nil
+}
+
+// fakeTransportStream keeps the header an RPC sends.
+type fakeTransportStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *fakeTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// catchAccessLog collects the access log lines for the rest of the test.
+func catchAccessLog(t *testing.T) *[]string {
+	var lines []string
+	previous := accessLogf
+	accessLogf = func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+	t.Cleanup(func() { accessLogf = previous })
+
+	return &lines
+}
+
+var requestIDTests = []struct {
+	name   string
+	sent   string // none when empty
+	wantID bool   // the sent ID is kept
+}{
+	{
+		name:   "valid",
+		sent:   "client-42",
+		wantID: true,
+	},
+	{
+		name: "invalid",
+		sent: "bad id\nrequest_id=forged",
+	},
+	{
+		name: "missing",
+	},
+}
+
+// requestIDContext is the context of an RPC sending id, if any, with a
+// transport stream to catch the header.
+func requestIDContext(t *testing.T, id string) (context.Context, *fakeTransportStream) {
+	ctx := t.Context()
+	if id != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(requestid.MetadataKey, id))
+	}
+	ts := &fakeTransportStream{}
+
+	return grpc.NewContextWithServerTransportStream(ctx, ts), ts
+}
+
+func assertRequestID(t *testing.T, sent string, wantID bool, got string, ts *fakeTransportStream) {
+	assert.True(t, requestid.Valid(got))
+	if wantID {
+		assert.Equal(t, sent, got)
+	} else {
+		assert.NotEqual(t, sent, got)
+	}
+	assert.Equal(t, []string{got}, ts.header.Get(requestid.MetadataKey))
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	for _, tt := range requestIDTests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, ts := requestIDContext(t, tt.sent)
+
+			var got string
+			_, err := RequestIDMiddleware()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: cafepb.CafeService_ExecuteBrew_FullMethodName},
+				func(ctx context.Context, _ any) (any, error) {
+					got = requestid.FromContext(ctx)
+					return nil, nil
+				})
+
+			assert.NoError(t, err)
+			assertRequestID(t, tt.sent, tt.wantID, got, ts)
+		})
+	}
+}
+
+func TestStreamRequestIDMiddleware(t *testing.T) {
+	for _, tt := range requestIDTests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, ts := requestIDContext(t, tt.sent)
+
+			var got string
+			err := StreamRequestIDMiddleware()(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: cafepb.CafeService_StreamBrew_FullMethodName},
+				func(_ any, ss grpc.ServerStream) error {
+					got = requestid.FromContext(ss.Context())
+					return nil
+				})
+
+			assert.NoError(t, err)
+			assertRequestID(t, tt.sent, tt.wantID, got, ts)
+		})
+	}
+}
+
+func TestMiddlewarePanic(t *testing.T) {
+	lines := catchAccessLog(t)
+	ctx, _ := requestIDContext(t, "client-42")
+
+	unary := chainUnary([]grpc.UnaryServerInterceptor{RequestIDMiddleware(), AccessLogMiddleware(), RecoveryMiddleware()})
+	_, err := unary(ctx, &cafepb.ExecuteBrewRequest{Orders: make([]*cafepb.Order, 3)}, &grpc.UnaryServerInfo{FullMethod: cafepb.CafeService_ExecuteBrew_FullMethodName},
+		func(context.Context, any) (any, error) {
+			panic("boom")
+		})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return StreamRequestIDMiddleware()(srv, ss, info, func(srv any, ss grpc.ServerStream) error {
+			return StreamAccessLogMiddleware()(srv, ss, info, func(srv any, ss grpc.ServerStream) error {
+				return StreamRecoveryMiddleware()(srv, ss, info, handler)
+			})
+		})
+	}
+	err = stream(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: cafepb.CafeService_StreamBrew_FullMethodName},
+		func(any, grpc.ServerStream) error {
+			panic("boom")
+		})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	assert.Len(t, *lines, 2)
+	for i, method := range []string{cafepb.CafeService_ExecuteBrew_FullMethodName, cafepb.CafeService_StreamBrew_FullMethodName} {
+		assert.Contains(t, (*lines)[i], "request_id=client-42 method="+method+" code=Internal")
+	}
+	assert.Contains(t, (*lines)[0], "orders=3")
+}
+
+// clientContext is the context of an RPC sent from host.
+func clientContext(t *testing.T, host string) context.Context {
+	return peer.NewContext(t.Context(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(host), Port: 50000}})
+}
+
+func TestAdmissionMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits admission.Limits
+		method string
+		orders int
+		hold   bool // the brew goes on after the request
+		want   []codes.Code
+	}{
+		{
+			name:   "rate limited",
+			limits: admission.Limits{Rate: 1, Burst: 1, MaxInFlight: 10, MaxOrders: 10, MaxBaristas: 10},
+			method: cafepb.CafeService_ExecuteBrew_FullMethodName,
+			orders: 1,
+			want:   []codes.Code{codes.OK, codes.ResourceExhausted},
+		},
+		{
+			name:   "slot given back once served",
+			limits: admission.Limits{Rate: 10, Burst: 10, MaxInFlight: 1, MaxOrders: 10, MaxBaristas: 10},
+			method: cafepb.CafeService_ExecuteBrew_FullMethodName,
+			orders: 1,
+			want:   []codes.Code{codes.OK, codes.OK},
+		},
+		{
+			name:   "slot held by the brew",
+			limits: admission.Limits{Rate: 10, Burst: 10, MaxInFlight: 1, MaxOrders: 10, MaxBaristas: 10},
+			method: cafepb.CafeService_SubmitOrders_FullMethodName,
+			orders: 1,
+			hold:   true,
+			want:   []codes.Code{codes.OK, codes.ResourceExhausted},
+		},
+		{
+			name:   "too large",
+			limits: admission.Limits{Rate: 10, Burst: 10, MaxInFlight: 10, MaxOrders: 2, MaxBaristas: 10},
+			method: cafepb.CafeService_ExecuteBrew_FullMethodName,
+			orders: 3,
+			want:   []codes.Code{codes.ResourceExhausted, codes.ResourceExhausted},
+		},
+		{
+			name:   "not a brew",
+			method: cafepb.CafeService_GetOrderStatus_FullMethodName,
+			want:   []codes.Code{codes.OK, codes.OK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := AdmissionMiddleware(admission.New(tt.limits, clock.NewVirtual(time.UnixMilli(0))))
+			req := &cafepb.ExecuteBrewRequest{Baristas: 1, Orders: make([]*cafepb.Order, tt.orders)}
+
+			for i, want := range tt.want {
+				served := false
+				_, err := interceptor(clientContext(t, "10.0.0.1"), req, &grpc.UnaryServerInfo{FullMethod: tt.method},
+					func(ctx context.Context, _ any) (any, error) {
+						served = true
+						if tt.hold {
+							admission.Hold(ctx)
+						}
+						return nil, nil
+					})
+
+				assert.Equal(t, want, status.Code(err), "request %d", i)
+				assert.Equal(t, want == codes.OK, served, "request %d", i)
+			}
+		})
+	}
+}
+
+func TestStreamAdmissionMiddleware(t *testing.T) {
+	limits := admission.Limits{Rate: 1, Burst: 1, MaxInFlight: 1, MaxOrders: 2, MaxBaristas: 10}
+
+	tests := []struct {
+		name     string
+		client   string
+		orders   int
+		wantCode codes.Code
+		wantRecv bool // the request got to the handler
+	}{
+		{
+			name:     "admitted",
+			client:   "10.0.0.1",
+			orders:   2,
+			wantCode: codes.OK,
+			wantRecv: true,
+		},
+		{
+			name:     "rate limited",
+			client:   "10.0.0.1",
+			orders:   1,
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name:     "too large",
+			client:   "10.0.0.2",
+			orders:   3,
+			wantCode: codes.ResourceExhausted,
+		},
+	}
+	// the cases share the controller, one after the other
+	interceptor := StreamAdmissionMiddleware(admission.New(limits, clock.NewVirtual(time.UnixMilli(0))))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss := &fakeServerStream{
+				ctx: clientContext(t, tt.client),
+				msg: &cafepb.ExecuteBrewRequest{Baristas: 1, Orders: make([]*cafepb.Order, tt.orders)},
+			}
+
+			received := false
+			err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: cafepb.CafeService_StreamBrew_FullMethodName},
+				func(_ any, ss grpc.ServerStream) error {
+					if err := ss.RecvMsg(&cafepb.ExecuteBrewRequest{}); err != nil {
+						return err
+					}
+					received = true
+					return nil
+				})
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantRecv, received)
+		})
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	methods := map[string]time.Duration{"ExecuteBrew": 5 * time.Second}
+
+	tests := []struct {
+		name           string
+		method         string
+		clientDeadline time.Duration
+		want           time.Duration
+	}{
+		{
+			name:   "default",
+			method: cafepb.CafeService_SubmitOrders_FullMethodName,
+			want:   time.Second,
+		},
+		{
+			name:   "method timeout over the default",
+			method: cafepb.CafeService_ExecuteBrew_FullMethodName,
+			want:   5 * time.Second,
+		},
+		{
+			name:           "earlier client deadline",
+			method:         cafepb.CafeService_ExecuteBrew_FullMethodName,
+			clientDeadline: 100 * time.Millisecond,
+			want:           100 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			if tt.clientDeadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.clientDeadline)
+				defer cancel()
+			}
+
+			start := time.Now()
+			_, err := TimeoutMiddleware(time.Second, methods)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, _ any) (any, error) {
+					deadline, ok := ctx.Deadline()
+					assert.True(t, ok)
+					assert.WithinDuration(t, start.Add(tt.want), deadline, 50*time.Millisecond)
+					return nil, nil
+				})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStreamTimeoutMiddleware(t *testing.T) {
+	methods := map[string]time.Duration{"StreamBrew": 30 * time.Second}
+
+	tests := []struct {
+		name   string
+		method string
+		want   time.Duration // none when zero
+	}{
+		{
+			name:   "no default",
+			method: "/pkg.proto.cafe.v1.CafeService/Other",
+		},
+		{
+			name:   "method timeout",
+			method: cafepb.CafeService_StreamBrew_FullMethodName,
+			want:   30 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			err := StreamTimeoutMiddleware(methods)(nil, &fakeServerStream{ctx: t.Context()}, &grpc.StreamServerInfo{FullMethod: tt.method},
+				func(_ any, ss grpc.ServerStream) error {
+					deadline, ok := ss.Context().Deadline()
+					assert.Equal(t, tt.want > 0, ok)
+					if ok {
+						assert.WithinDuration(t, start.Add(tt.want), deadline, 50*time.Millisecond)
+					}
+					return nil
+				})
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -1,9 +1,20 @@
 package config
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 type Config struct {
-	AppEnv string       `mapstructure:"APP_ENV"`
-	Grpc   GrpcConfig   `mapstructure:",squash"`
-	Logger LoggerConfig `mapstructure:",squash"`
+	AppEnv    string          `mapstructure:"APP_ENV"`
+	Grpc      GrpcConfig      `mapstructure:",squash"`
+	Http      HttpConfig      `mapstructure:",squash"`
+	Metrics   MetricsConfig   `mapstructure:",squash"`
+	Logger    LoggerConfig    `mapstructure:",squash"`
+	Brew      BrewConfig      `mapstructure:",squash"`
+	Menu      MenuConfig      `mapstructure:",squash"`
+	Admission AdmissionConfig `mapstructure:",squash"`
 }
 
 type LoggerConfig struct {
@@ -13,4 +24,72 @@ type LoggerConfig struct {
 
 type GrpcConfig struct {
 	Port int `mapstructure:"GRPC_PORT" validate:"required"`
+	// DrainTimeout is how long a shutdown waits for the RPCs in flight
+	// before cancelling them, e.g. "10s".
+	DrainTimeout time.Duration `mapstructure:"GRPC_DRAIN_TIMEOUT" validate:"required"`
+	// Timeout bounds the unary RPCs whose method has none in MethodTimeouts.
+	Timeout time.Duration `mapstructure:"GRPC_TIMEOUT" validate:"required"`
+	// MethodTimeouts bounds RPCs by method name, streaming ones included,
+	// e.g. "ExecuteBrew=5s,StreamBrew=30s".
+	MethodTimeouts string `mapstructure:"GRPC_METHOD_TIMEOUTS"`
+}
+
+// Timeouts parses MethodTimeouts by method name.
+func (c GrpcConfig) Timeouts() (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for entry := range strings.SplitSeq(c.MethodTimeouts, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		method, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("method timeout %q: want method=duration", entry)
+		}
+		method = strings.TrimSpace(method)
+		if method == "" {
+			return nil, fmt.Errorf("method timeout %q: want method=duration", entry)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("method timeout %q: %w", entry, err)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("method timeout %q: must be positive", entry)
+		}
+		timeouts[method] = timeout
+	}
+
+	return timeouts, nil
+}
+
+// HttpConfig is the JSON gateway of the brew and stats RPCs. It is drained
+// along with the gRPC server, within GRPC_DRAIN_TIMEOUT.
+type HttpConfig struct {
+	Port int `mapstructure:"HTTP_PORT" validate:"required"`
+}
+
+type MetricsConfig struct {
+	Port int `mapstructure:"METRICS_PORT" validate:"required"`
+}
+
+type BrewConfig struct {
+	Scheduler  string `mapstructure:"BREW_SCHEDULER" validate:"required,oneof=fifo spt lpt round_robin edf"`
+	Simulation bool   `mapstructure:"BREW_SIMULATION"`
+}
+
+type MenuConfig struct {
+	File string `mapstructure:"MENU_FILE" validate:"required"`
+}
+
+// AdmissionConfig limits the brew requests. A client, told apart by its
+// address, may send RateLimit requests per second on average and up to
+// RateBurst at once.
+type AdmissionConfig struct {
+	RateLimit   float64 `mapstructure:"ADMISSION_RATE_LIMIT" validate:"required,gt=0"`
+	RateBurst   int     `mapstructure:"ADMISSION_RATE_BURST" validate:"required,gt=0"`
+	MaxInFlight int     `mapstructure:"ADMISSION_MAX_IN_FLIGHT" validate:"required,gt=0"`
+	MaxOrders   int     `mapstructure:"ADMISSION_MAX_ORDERS" validate:"required,gt=0"`
+	MaxBaristas int     `mapstructure:"ADMISSION_MAX_BARISTAS" validate:"required,gt=0"`
 }
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrpcConfigTimeouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]time.Duration
+		wantErr bool
+	}{
+		{
+			name:  "none",
+			value: "",
+			want:  map[string]time.Duration{},
+		},
+		{
+			name:  "by method",
+			value: "ExecuteBrew=5s, StreamBrew = 1m,",
+			want:  map[string]time.Duration{"ExecuteBrew": 5 * time.Second, "StreamBrew": time.Minute},
+		},
+		{
+			name:    "no duration",
+			value:   "ExecuteBrew",
+			wantErr: true,
+		},
+		{
+			name:    "bad duration",
+			value:   "ExecuteBrew=soon",
+			wantErr: true,
+		},
+		{
+			name:    "not positive",
+			value:   "ExecuteBrew=0s",
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			value:   "ExecuteBrew=-1s",
+			wantErr: true,
+		},
+		{
+			name:    "no method",
+			value:   " = 5s",
+			wantErr: true,
+		},
+		{
+			name:    "one bad entry among good ones",
+			value:   "ExecuteBrew=5s,StreamBrew=1m,SubmitOrders",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := GrpcConfig{MethodTimeouts: test.value}.Timeouts()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package admission
+
+import (
+	"context"
+	"fmt"
+	"gopher-cafe/internal/clock"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apperrors "gopher-cafe/internal/errors"
+)
+
+// sweepEvery is how often the buckets of the clients gone quiet are dropped.
+const sweepEvery = time.Minute
+
+// Limits bound the brew requests. Every client gets a token bucket of Burst
+// requests refilled at Rate requests per second.
+type Limits struct {
+	Rate        float64
+	Burst       int
+	MaxInFlight int
+	MaxOrders   int
+	MaxBaristas int
+}
+
+// Controller admits the brew requests within the limits.
+type Controller struct {
+	limits Limits
+	clock  clock.Clock
+	slots  chan struct{} // one per brew in flight
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	at     time.Time // of the last refill
+}
+
+func New(limits Limits, clk clock.Clock) *Controller {
+	return &Controller{
+		limits:    limits,
+		clock:     clk,
+		slots:     make(chan struct{}, limits.MaxInFlight),
+		buckets:   make(map[string]*bucket),
+		lastSweep: clk.Now(),
+	}
+}
+
+// Admit takes a token of the bucket of client and a slot of the brews in
+// flight, to be given back with the returned release once the brew is done.
+// It fails with ErrRateLimited or ErrOverloaded rather than waiting.
+func (c *Controller) Admit(client string) (func(), error) {
+	if !c.take(client) {
+		return nil, fmt.Errorf("%w: %s may send %g brew requests per second", apperrors.ErrRateLimited, client, c.limits.Rate)
+	}
+
+	select {
+	case c.slots <- struct{}{}:
+	default:
+		return nil, fmt.Errorf("%w: at most %d", apperrors.ErrOverloaded, c.limits.MaxInFlight)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-c.slots })
+	}, nil
+}
+
+type slotKey struct{}
+
+// slot is the place of an admitted request among the brews in flight.
+type slot struct {
+	release func()
+	held    atomic.Bool
+}
+
+// NewContext returns a copy of ctx carrying the release of an admitted
+// request, and a done to call once the request is served. done gives the slot
+// back unless the request handed it over to its brew with Hold.
+func NewContext(ctx context.Context, release func()) (context.Context, func()) {
+	s := &slot{release: release}
+
+	return context.WithValue(ctx, slotKey{}, s), func() {
+		if !s.held.Load() {
+			release()
+		}
+	}
+}
+
+// Hold hands the slot of the request of ctx over to a brew that goes on after
+// the request is served, and returns the release to call once it is done. It
+// must be called before the request returns, and returns a no-op when ctx has
+// no slot.
+func Hold(ctx context.Context) func() {
+	s, ok := ctx.Value(slotKey{}).(*slot)
+	if !ok || !s.held.CompareAndSwap(false, true) {
+		return func() {}
+	}
+
+	return s.release
+}
+
+// Check fails with ErrTooLarge when a request has more orders or baristas
+// than allowed.
+func (c *Controller) Check(orders, baristas int) error {
+	if orders > c.limits.MaxOrders {
+		return fmt.Errorf("%w: %d orders, at most %d", apperrors.ErrTooLarge, orders, c.limits.MaxOrders)
+	}
+	if baristas > c.limits.MaxBaristas {
+		return fmt.Errorf("%w: %d baristas, at most %d", apperrors.ErrTooLarge, baristas, c.limits.MaxBaristas)
+	}
+
+	return nil
+}
+
+// take takes a token of the bucket of client, if one is left.
+func (c *Controller) take(client string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	c.sweep(now)
+
+	b, ok := c.buckets[client]
+	if !ok {
+		b = &bucket{tokens: float64(c.limits.Burst), at: now}
+		c.buckets[client] = b
+	}
+	c.refill(b, now)
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
+
+// refill adds the tokens earned since the last refill. c.mu must be held.
+func (c *Controller) refill(b *bucket, now time.Time) {
+	b.tokens = min(float64(c.limits.Burst), b.tokens+now.Sub(b.at).Seconds()*c.limits.Rate)
+	b.at = now
+}
+
+// sweep drops the buckets that are full again, as they are no different from
+// new ones. c.mu must be held.
+func (c *Controller) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < sweepEvery {
+		return
+	}
+	c.lastSweep = now
+
+	for client, b := range c.buckets {
+		c.refill(b, now)
+		if b.tokens >= float64(c.limits.Burst) {
+			delete(c.buckets, client)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package admission
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gopher-cafe/internal/clock"
+
+	apperrors "gopher-cafe/internal/errors"
+)
+
+func TestAdmitRate(t *testing.T) {
+	clk := clock.NewVirtual(time.UnixMilli(0))
+	c := New(Limits{Rate: 2, Burst: 2, MaxInFlight: 10}, clk)
+
+	admit := func(client string) error {
+		release, err := c.Admit(client)
+		if err == nil {
+			release()
+		}
+		return err
+	}
+
+	// the burst goes through at once
+	assert.NoError(t, admit("a"))
+	assert.NoError(t, admit("a"))
+	assert.ErrorIs(t, admit("a"), apperrors.ErrRateLimited)
+	// every client has a bucket of its own
+	assert.NoError(t, admit("b"))
+
+	// a token is back every 500ms
+	clk.Advance(400 * time.Millisecond)
+	assert.ErrorIs(t, admit("a"), apperrors.ErrRateLimited)
+	clk.Advance(100 * time.Millisecond)
+	assert.NoError(t, admit("a"))
+	assert.ErrorIs(t, admit("a"), apperrors.ErrRateLimited)
+
+	// the buckets of the quiet clients are dropped
+	clk.Advance(sweepEvery)
+	assert.NoError(t, admit("c"))
+	c.mu.Lock()
+	assert.Len(t, c.buckets, 1)
+	c.mu.Unlock()
+}
+
+func TestAdmitInFlight(t *testing.T) {
+	c := New(Limits{Rate: 100, Burst: 100, MaxInFlight: 2}, clock.NewVirtual(time.UnixMilli(0)))
+
+	release1, err := c.Admit("a")
+	assert.NoError(t, err)
+	release2, err := c.Admit("b")
+	assert.NoError(t, err)
+
+	_, err = c.Admit("c")
+	assert.ErrorIs(t, err, apperrors.ErrOverloaded)
+
+	// releasing twice gives back a single slot
+	release1()
+	release1()
+	release3, err := c.Admit("c")
+	assert.NoError(t, err)
+	_, err = c.Admit("c")
+	assert.ErrorIs(t, err, apperrors.ErrOverloaded)
+
+	release2()
+	release3()
+}
+
+func TestCheck(t *testing.T) {
+	c := New(Limits{MaxOrders: 10, MaxBaristas: 4}, clock.New())
+
+	tests := []struct {
+		name     string
+		orders   int
+		baristas int
+		wantErr  error
+	}{
+		{name: "within", orders: 10, baristas: 4},
+		{name: "too many orders", orders: 11, baristas: 1, wantErr: apperrors.ErrTooLarge},
+		{name: "too many baristas", orders: 1, baristas: 5, wantErr: apperrors.ErrTooLarge},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ErrorIs(t, c.Check(test.orders, test.baristas), test.wantErr)
+		})
+	}
+}
+
+func TestHold(t *testing.T) {
+	c := New(Limits{Rate: 100, Burst: 100, MaxInFlight: 1}, clock.NewVirtual(time.UnixMilli(0)))
+
+	// the slot is given back once the request is served
+	release, err := c.Admit("a")
+	assert.NoError(t, err)
+	_, done := NewContext(t.Context(), release)
+	done()
+
+	// or once the brew holding it is done
+	release, err = c.Admit("a")
+	assert.NoError(t, err)
+	ctx, done := NewContext(t.Context(), release)
+	held := Hold(ctx)
+	// a slot is held once
+	Hold(ctx)()
+	done()
+	_, err = c.Admit("b")
+	assert.ErrorIs(t, err, apperrors.ErrOverloaded)
+
+	held()
+	release, err = c.Admit("b")
+	assert.NoError(t, err)
+	release()
+
+	// a context without a slot has nothing to hold
+	Hold(t.Context())()
+}
@@ -0,0 +1,103 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of the simulation, so that time can be faked in
+// tests and in the discrete-event simulation.
+type Clock interface {
+	Now() time.Time
+	// After sends the current time on the returned channel once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+// New returns a Clock backed by the wall clock.
+func New() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Virtual is a Clock that only moves when told to. Channels returned by After
+// fire as soon as the virtual time reaches their deadline.
+type Virtual struct {
+	now     time.Time
+	waiters []waiter
+	mu      sync.Mutex
+}
+
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func NewVirtual(start time.Time) *Virtual {
+	return &Virtual{now: start}
+}
+
+func (v *Virtual) Now() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.now
+}
+
+func (v *Virtual) After(d time.Duration) <-chan time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- v.now
+		return ch
+	}
+
+	v.waiters = append(v.waiters, waiter{at: v.now.Add(d), ch: ch})
+	return ch
+}
+
+// Pending returns the number of After channels that have yet to fire, so that
+// tests can wait for the timers of a goroutine before moving the time.
+func (v *Virtual) Pending() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return len(v.waiters)
+}
+
+// Advance moves the virtual time forward by d.
+func (v *Virtual) Advance(d time.Duration) {
+	v.Set(v.Now().Add(d))
+}
+
+// Set moves the virtual time to t, firing every After deadline reached on the
+// way. Time never goes backwards.
+func (v *Virtual) Set(t time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if t.Before(v.now) {
+		return
+	}
+	v.now = t
+
+	pending := v.waiters[:0]
+	for _, w := range v.waiters {
+		if w.at.After(t) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- t
+	}
+	v.waiters = pending
+}
@@ -1,6 +1,12 @@
 package coffeeshop
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	apperrors "gopher-cafe/internal/errors"
+)
 
 type EquipmentType int
 
@@ -29,54 +35,437 @@ func (e EquipmentType) String() string {
 	}
 }
 
-type DrinkType int
+// ParseEquipmentType parses the name of an equipment, either as printed by
+// String or in snake case, e.g. "EspressoMachine" or "espresso_machine".
+func ParseEquipmentType(name string) (EquipmentType, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(name, "_", ""))
+	for _, equip := range []EquipmentType{EquipGrinder, EquipEspressoMachine, EquipMilkSteamer, EquipBlender, EquipWhisk} {
+		if normalized == strings.ToLower(equip.String()) {
+			return equip, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown equipment %q", name)
+}
+
+// BreakdownPolicy tells what happens to the steps sent to an equipment while
+// all of its workers are broken down.
+type BreakdownPolicy string
+
+const (
+	// BreakdownWait keeps the steps queued until a worker is repaired.
+	BreakdownWait BreakdownPolicy = "wait"
+	// BreakdownFail fails the steps right away.
+	BreakdownFail BreakdownPolicy = "fail"
+)
+
+// FaultModel describes how the workers of an equipment break down and how
+// often they are cleaned. A worker takes no steps while it is repaired or
+// cleaned. The zero value never breaks down nor needs cleaning.
+type FaultModel struct {
+	MTBF         time.Duration // mean processing time between two breakdowns of a worker
+	RepairTime   time.Duration
+	CleanEvery   int // steps after which a worker is cleaned
+	CleaningTime time.Duration
+	OnBreakdown  BreakdownPolicy
+}
+
+// DrinkType is the name of a drink on the menu.
+type DrinkType string
 
 const (
-	DrinkUnspecified DrinkType = iota
-	DrinkEspresso
-	DrinkLatte
-	DrinkFrappe
-	DrinkMatcha
+	DrinkUnspecified DrinkType = ""
+	DrinkEspresso    DrinkType = "espresso"
+	DrinkLatte       DrinkType = "latte"
+	DrinkFrappe      DrinkType = "frappe"
+	DrinkMatcha      DrinkType = "matcha"
 )
 
+func (d DrinkType) String() string {
+	if d == DrinkUnspecified {
+		return "unspecified"
+	}
+
+	return string(d)
+}
+
+// Ingredient is the name of an ingredient in stock. Quantities of an
+// ingredient are counted in the unit it is stocked in, e.g. grams of beans or
+// millilitres of milk.
+type Ingredient string
+
+const (
+	IngredientBeans  Ingredient = "beans"
+	IngredientMilk   Ingredient = "milk"
+	IngredientMatcha Ingredient = "matcha_powder"
+	IngredientIce    Ingredient = "ice"
+)
+
+// IngredientLevel is the quantity of an ingredient in stock. Falling below
+// LowStock is worth a restock.
+type IngredientLevel struct {
+	Ingredient Ingredient
+	Quantity   int64
+	LowStock   int64
+}
+
+// RecipeStep is a node of a recipe graph. DependsOn holds the indices of the
+// steps in the same recipe that must finish before this step can start, so
+// steps without a path between them may run at the same time.
 type RecipeStep struct {
 	Equipment EquipmentType
 	Duration  time.Duration
+	DependsOn []int
 }
 
-var Recipes = map[DrinkType][]RecipeStep{
-	DrinkEspresso: {
-		{EquipGrinder, 5 * time.Millisecond},
-		{EquipEspressoMachine, 8 * time.Millisecond},
-	},
-	DrinkLatte: {
-		{EquipGrinder, 5 * time.Millisecond},
-		{EquipEspressoMachine, 8 * time.Millisecond},
-		{EquipMilkSteamer, 15 * time.Millisecond},
-	},
-	DrinkFrappe: {
-		{EquipGrinder, 5 * time.Millisecond},
-		{EquipBlender, 12 * time.Millisecond},
-	},
-	DrinkMatcha: {
-		{EquipGrinder, 5 * time.Millisecond},
-		{EquipMilkSteamer, 15 * time.Millisecond},
-		{EquipWhisk, 3 * time.Millisecond},
-	},
+// Order is a drink to brew. DueAtMs is the Unix time in milliseconds the
+// order was promised for, e.g. the pickup time of a mobile order, or zero
+// when it has no deadline.
+type Order struct {
+	ID        int64
+	Drink     DrinkType
+	Modifiers Modifiers
+	DueAtMs   int64
+	Priority  Priority
 }
 
-type Order struct {
-	ID    int64
-	Drink DrinkType
+// Priority is the class of an order. The equipment serves the waiting steps
+// of the higher classes first. The zero value is a walk-in.
+type Priority string
+
+const (
+	PriorityWalkIn Priority = ""
+	PriorityMobile Priority = "mobile"
+	PriorityVIP    Priority = "vip"
+)
+
+// PriorityAging is the waiting time that makes up for one priority class:
+// a walk-in step that has waited more than 2×PriorityAging longer than a VIP
+// step goes first, so the lower classes do not starve.
+const PriorityAging = 50 * time.Millisecond
+
+func (p Priority) String() string {
+	if p == PriorityWalkIn {
+		return "walk_in"
+	}
+
+	return string(p)
 }
 
+// Rank is the number of classes below the priority.
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityMobile:
+		return 1
+	case PriorityVIP:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// QueueKey ranks a step of the priority that started waiting for its
+// equipment at queuedAt among the other waiting steps, the lowest key going
+// first. Aging shifts every waiting step at the same pace, so the keys do not
+// change while the steps wait.
+func (p Priority) QueueKey(queuedAt time.Time) int64 {
+	return queuedAt.UnixNano() - int64(p.Rank())*int64(PriorityAging)
+}
+
+// Size of a drink. The zero value is a medium.
+type Size string
+
+const (
+	SizeUnspecified Size = ""
+	SizeSmall       Size = "small"
+	SizeMedium      Size = "medium"
+	SizeLarge       Size = "large"
+)
+
+// Scale is the factor the step durations and the ingredient quantities of a
+// recipe are scaled by, a recipe being written for a medium.
+func (s Size) Scale() float64 {
+	switch s {
+	case SizeSmall:
+		return 0.75
+	case SizeLarge:
+		return 1.25
+	default:
+		return 1
+	}
+}
+
+// Milk is a milk replacing the milk of a recipe. The zero value keeps it.
+type Milk string
+
+const (
+	MilkRegular Milk = ""
+	MilkOat     Milk = "oat"
+	MilkSoy     Milk = "soy"
+	MilkAlmond  Milk = "almond"
+)
+
+// Ingredient is the ingredient the milk is stocked as, e.g. "oat_milk".
+func (m Milk) Ingredient() Ingredient {
+	if m == MilkRegular {
+		return IngredientMilk
+	}
+
+	return Ingredient(string(m) + "_milk")
+}
+
+const (
+	// MaxExtraShots is the number of extra espresso shots a drink can take.
+	MaxExtraShots = 3
+	// IcedQuantity is the quantity of ice added to a drink served iced.
+	IcedQuantity = 100
+)
+
+// Modifiers customize the recipe of an ordered drink.
+type Modifiers struct {
+	Size       Size
+	ExtraShots int // espresso shots pulled after the last one of the recipe
+	Milk       Milk
+	Iced       bool // served over ice rather than with steamed milk
+}
+
+// StepExecution spans from StartTimeMs, when the step asked for its
+// equipment, to EndTimeMs. QueuedAtMs, AcquiredAtMs and ReleasedAtMs split
+// that span into the time spent waiting for a free worker and the time
+// WorkerID spent processing the step.
 type StepExecution struct {
-	Equipment   EquipmentType
-	StartTimeMs int64
-	EndTimeMs   int64
+	Equipment    EquipmentType
+	StartTimeMs  int64
+	EndTimeMs    int64
+	QueuedAtMs   int64
+	AcquiredAtMs int64
+	ReleasedAtMs int64
+	WorkerID     int
 }
 
+// ValidateOrders checks the orders of a request, reporting every order with a
+// non-positive or duplicate ID, without a drink, with an unknown priority or
+// with out of range modifiers by its index. Whether the drink is on the menu
+// and the modifiers apply to it is left to the menu.
+func ValidateOrders(orders []Order) error {
+	var violations []apperrors.Violation
+
+	seen := make(map[int64]int, len(orders))
+	for i, order := range orders {
+		if order.ID <= 0 {
+			violations = append(violations, apperrors.Violation{
+				Field:  fmt.Sprintf("orders[%d].id", i),
+				Reason: "must be positive",
+			})
+		} else if first, ok := seen[order.ID]; ok {
+			violations = append(violations, apperrors.Violation{
+				Field:  fmt.Sprintf("orders[%d].id", i),
+				Reason: fmt.Sprintf("duplicate of orders[%d].id", first),
+			})
+		} else {
+			seen[order.ID] = i
+		}
+
+		if order.Drink == DrinkUnspecified {
+			violations = append(violations, apperrors.Violation{
+				Field:  fmt.Sprintf("orders[%d].drink", i),
+				Reason: "unspecified or unknown drink",
+			})
+		}
+
+		if order.DueAtMs < 0 {
+			violations = append(violations, apperrors.Violation{
+				Field:  fmt.Sprintf("orders[%d].due_at_ms", i),
+				Reason: "cannot be negative",
+			})
+		}
+
+		switch order.Priority {
+		case PriorityWalkIn, PriorityMobile, PriorityVIP:
+		default:
+			violations = append(violations, apperrors.Violation{
+				Field:  fmt.Sprintf("orders[%d].priority", i),
+				Reason: fmt.Sprintf("unknown priority %q", order.Priority),
+			})
+		}
+
+		violations = append(violations, validateModifiers(i, order.Modifiers)...)
+	}
+
+	if len(violations) > 0 {
+		return &apperrors.ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+func validateModifiers(i int, mods Modifiers) []apperrors.Violation {
+	var violations []apperrors.Violation
+
+	switch mods.Size {
+	case SizeUnspecified, SizeSmall, SizeMedium, SizeLarge:
+	default:
+		violations = append(violations, apperrors.Violation{
+			Field:  fmt.Sprintf("orders[%d].modifiers.size", i),
+			Reason: fmt.Sprintf("unknown size %q", mods.Size),
+		})
+	}
+
+	if mods.ExtraShots < 0 || mods.ExtraShots > MaxExtraShots {
+		violations = append(violations, apperrors.Violation{
+			Field:  fmt.Sprintf("orders[%d].modifiers.extra_shots", i),
+			Reason: fmt.Sprintf("must be between 0 and %d", MaxExtraShots),
+		})
+	}
+
+	switch mods.Milk {
+	case MilkRegular, MilkOat, MilkSoy, MilkAlmond:
+	default:
+		violations = append(violations, apperrors.Violation{
+			Field:  fmt.Sprintf("orders[%d].modifiers.milk", i),
+			Reason: fmt.Sprintf("unknown milk %q", mods.Milk),
+		})
+	}
+
+	return violations
+}
+
+type OrderStatus int
+
+const (
+	OrderStatusUnspecified OrderStatus = iota
+	OrderStatusCompleted
+	OrderStatusFailed
+	OrderStatusCancelled
+)
+
+func (s OrderStatus) String() string {
+	switch s {
+	case OrderStatusCompleted:
+		return "Completed"
+	case OrderStatusFailed:
+		return "Failed"
+	case OrderStatusCancelled:
+		return "Cancelled"
+	default:
+		return "Unspecified"
+	}
+}
+
+// OrderResult is the outcome of an order. Orders that did not complete have
+// no steps and tell why in Error. LatenessMs is how late a completed order
+// with a due time was, negative when it was early.
 type OrderResult struct {
-	OrderID int64
-	Steps   []StepExecution
+	OrderID    int64
+	Drink      DrinkType
+	Priority   Priority
+	Status     OrderStatus
+	Error      string
+	Steps      []StepExecution
+	DueAtMs    int64
+	LatenessMs int64
+}
+
+// CompletedAtMs is when the last step of the order ended, zero for an order
+// without steps.
+func (r OrderResult) CompletedAtMs() int64 {
+	var end int64
+	// steps may run in parallel, so the last step is not necessarily the
+	// last one to finish
+	for _, step := range r.Steps {
+		end = max(end, step.EndTimeMs)
+	}
+
+	return end
+}
+
+// WithDue returns the result of an order due at dueAtMs, telling how late it
+// completed.
+func (r OrderResult) WithDue(dueAtMs int64) OrderResult {
+	r.DueAtMs = dueAtMs
+	r.LatenessMs = 0
+	if dueAtMs != 0 && r.Status == OrderStatusCompleted {
+		r.LatenessMs = r.CompletedAtMs() - dueAtMs
+	}
+
+	return r
+}
+
+// MetDeadline tells whether an order with a due time completed by then.
+func (r OrderResult) MetDeadline() bool {
+	return r.DueAtMs != 0 && r.Status == OrderStatusCompleted && r.LatenessMs <= 0
+}
+
+// SchedulingStrategy decides the order in which baristas pick up the orders of
+// a brew request.
+type SchedulingStrategy string
+
+const (
+	SchedulingFIFO          SchedulingStrategy = "fifo"
+	SchedulingShortestFirst SchedulingStrategy = "spt"
+	SchedulingLongestFirst  SchedulingStrategy = "lpt"
+	SchedulingRoundRobin    SchedulingStrategy = "round_robin"
+	SchedulingDeadline      SchedulingStrategy = "edf"
+)
+
+func ParseSchedulingStrategy(s string) (SchedulingStrategy, error) {
+	switch strategy := SchedulingStrategy(s); strategy {
+	case SchedulingFIFO, SchedulingShortestFirst, SchedulingLongestFirst, SchedulingRoundRobin, SchedulingDeadline:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown scheduling strategy %q", s)
+	}
+}
+
+// RecipeDuration is the time a recipe needs on idle equipment, which is the
+// length of the longest dependency chain of its steps.
+func RecipeDuration(recipe []RecipeStep) time.Duration {
+	finish := make([]time.Duration, len(recipe))
+	visited := make([]bool, len(recipe))
+
+	var finishAt func(i int) time.Duration
+	finishAt = func(i int) time.Duration {
+		if visited[i] {
+			return finish[i]
+		}
+		visited[i] = true
+
+		var start time.Duration
+		for _, dep := range recipe[i].DependsOn {
+			start = max(start, finishAt(dep))
+		}
+		finish[i] = start + recipe[i].Duration
+
+		return finish[i]
+	}
+
+	var total time.Duration
+	for i := range recipe {
+		total = max(total, finishAt(i))
+	}
+
+	return total
+}
+
+type BrewEventType int
+
+const (
+	BrewEventStepStarted BrewEventType = iota + 1
+	BrewEventStepFinished
+	BrewEventOrderCompleted
+	BrewEventOrderFailed
+	BrewEventOrderState
+)
+
+// BrewEvent reports the progress of a brew. Step events carry the step, its
+// index in the recipe and, once finished, its full execution; order events
+// carry the order result and state events the transition of the order.
+type BrewEvent struct {
+	Type       BrewEventType
+	OrderID    int64
+	TimeMs     int64
+	StepIndex  int
+	Step       StepExecution
+	Result     OrderResult
+	Transition Transition
 }
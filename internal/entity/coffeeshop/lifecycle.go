@@ -0,0 +1,130 @@
+package coffeeshop
+
+import (
+	"fmt"
+	"slices"
+
+	apperrors "gopher-cafe/internal/errors"
+)
+
+// OrderState is where an order stands in its lifecycle.
+type OrderState int
+
+const (
+	OrderStateUnspecified OrderState = iota
+	OrderStateReceived
+	OrderStateQueued
+	OrderStateBrewing
+	OrderStateReady
+	OrderStatePickedUp
+	OrderStateCancelled
+	OrderStateFailed
+)
+
+func (s OrderState) String() string {
+	switch s {
+	case OrderStateReceived:
+		return "Received"
+	case OrderStateQueued:
+		return "Queued"
+	case OrderStateBrewing:
+		return "Brewing"
+	case OrderStateReady:
+		return "Ready"
+	case OrderStatePickedUp:
+		return "PickedUp"
+	case OrderStateCancelled:
+		return "Cancelled"
+	case OrderStateFailed:
+		return "Failed"
+	default:
+		return "Unspecified"
+	}
+}
+
+// Final tells whether the state of the order no longer changes.
+func (s OrderState) Final() bool {
+	return len(orderTransitions[s]) == 0
+}
+
+// Brewed tells whether the brew of the order is over, the order being ready
+// or final.
+func (s OrderState) Brewed() bool {
+	return s == OrderStateReady || s.Final()
+}
+
+// orderTransitions are the states an order may go to from each state. An
+// order is brewing once its first step starts, and stays so as the next steps
+// start.
+var orderTransitions = map[OrderState][]OrderState{
+	OrderStateReceived: {OrderStateQueued, OrderStateCancelled, OrderStateFailed},
+	OrderStateQueued:   {OrderStateBrewing, OrderStateCancelled, OrderStateFailed},
+	OrderStateBrewing:  {OrderStateBrewing, OrderStateReady, OrderStateCancelled, OrderStateFailed},
+	OrderStateReady:    {OrderStatePickedUp},
+}
+
+// Transition is a change of state of an order. Step is the recipe index of
+// the step that started when To is OrderStateBrewing.
+type Transition struct {
+	From OrderState
+	To   OrderState
+	Step int
+	AtMs int64
+}
+
+// Lifecycle is the state machine of an order, from the time it is received
+// until it is picked up, cancelled or failed. It keeps every transition, the
+// first one being the order received.
+type Lifecycle struct {
+	Transitions []Transition
+}
+
+func NewLifecycle(receivedAtMs int64) Lifecycle {
+	return Lifecycle{Transitions: []Transition{
+		{To: OrderStateReceived, AtMs: receivedAtMs},
+	}}
+}
+
+// State returns the current state of the order.
+func (l Lifecycle) State() OrderState {
+	if len(l.Transitions) == 0 {
+		return OrderStateUnspecified
+	}
+
+	return l.Transitions[len(l.Transitions)-1].To
+}
+
+// Step returns the recipe index of the last step started, or -1 before the
+// order is brewing.
+func (l Lifecycle) Step() int {
+	for _, tr := range slices.Backward(l.Transitions) {
+		if tr.To == OrderStateBrewing {
+			return tr.Step
+		}
+	}
+
+	return -1
+}
+
+// Transition moves the order to state to at atMs, step being the recipe index
+// of the step that started when to is OrderStateBrewing. An illegal transition
+// returns an ErrIllegalTransition error and leaves the lifecycle as it was.
+func (l *Lifecycle) Transition(to OrderState, step int, atMs int64) (Transition, error) {
+	from := l.State()
+	if !slices.Contains(orderTransitions[from], to) {
+		return Transition{}, fmt.Errorf("%w: %s to %s", apperrors.ErrIllegalTransition, from, to)
+	}
+
+	tr := Transition{From: from, To: to, AtMs: atMs}
+	if to == OrderStateBrewing {
+		tr.Step = step
+	}
+	l.Transitions = append(l.Transitions, tr)
+
+	return tr, nil
+}
+
+// Clone returns a copy of the lifecycle that does not share its transitions.
+func (l Lifecycle) Clone() Lifecycle {
+	return Lifecycle{Transitions: slices.Clone(l.Transitions)}
+}
@@ -0,0 +1,94 @@
+package coffeeshop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apperrors "gopher-cafe/internal/errors"
+)
+
+func TestLifecycleTransition(t *testing.T) {
+	type move struct {
+		to   OrderState
+		step int
+	}
+
+	tests := []struct {
+		name     string
+		moves    []move
+		want     OrderState
+		wantStep int
+		wantErr  error
+	}{
+		{
+			name: "picked up",
+			moves: []move{
+				{to: OrderStateQueued},
+				{to: OrderStateBrewing, step: 0},
+				{to: OrderStateBrewing, step: 2},
+				{to: OrderStateBrewing, step: 1},
+				{to: OrderStateReady},
+				{to: OrderStatePickedUp},
+			},
+			want:     OrderStatePickedUp,
+			wantStep: 1,
+		},
+		{
+			name:     "cancelled while queued",
+			moves:    []move{{to: OrderStateQueued}, {to: OrderStateCancelled}},
+			want:     OrderStateCancelled,
+			wantStep: -1,
+		},
+		{
+			name:     "not ready before brewing",
+			moves:    []move{{to: OrderStateQueued}, {to: OrderStateReady}},
+			want:     OrderStateQueued,
+			wantStep: -1,
+			wantErr:  apperrors.ErrIllegalTransition,
+		},
+		{
+			name:     "no going back",
+			moves:    []move{{to: OrderStateQueued}, {to: OrderStateReceived}},
+			want:     OrderStateQueued,
+			wantStep: -1,
+			wantErr:  apperrors.ErrIllegalTransition,
+		},
+		{
+			name: "final once failed",
+			moves: []move{
+				{to: OrderStateQueued},
+				{to: OrderStateBrewing, step: 0},
+				{to: OrderStateFailed},
+				{to: OrderStateBrewing, step: 1},
+			},
+			want:     OrderStateFailed,
+			wantStep: 0,
+			wantErr:  apperrors.ErrIllegalTransition,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := NewLifecycle(0)
+
+			var err error
+			for i, m := range test.moves {
+				var tr Transition
+				tr, err = l.Transition(m.to, m.step, int64(i+1))
+				if err != nil {
+					break
+				}
+				assert.Equal(t, m.to, tr.To)
+				assert.Equal(t, int64(i+1), tr.AtMs)
+			}
+
+			assert.ErrorIs(t, err, test.wantErr)
+			assert.Equal(t, test.want, l.State())
+			assert.Equal(t, test.wantStep, l.Step())
+			assert.Equal(t, OrderStateReceived, l.Transitions[0].To)
+			for i, tr := range l.Transitions[1:] {
+				assert.Equal(t, l.Transitions[i].To, tr.From)
+			}
+		})
+	}
+}
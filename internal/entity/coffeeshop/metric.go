@@ -1,24 +1,79 @@
 package coffeeshop
 
 import (
+	"maps"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/rcrowley/go-metrics"
 )
 
+// DurationBucketsMs are the upper bounds of the order duration histograms.
+var DurationBucketsMs = []int64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
 type OrderMetrics struct {
 	totalRequests int64
 	totalOrders   int64
+	dueOrders     int64 // orders with a due time
+	onTimeOrders  int64 // orders that met their due time
 
 	histogram metrics.Histogram
 
+	duration         durationHistogram
+	drinkDuration    map[DrinkType]*durationHistogram
+	priorityDuration map[Priority]*durationHistogram
+	transitions      map[OrderState]int64 // by the state the orders went to
+
 	mu sync.Mutex
 }
 
+// DurationHistogram holds cumulative counts per DurationBucketsMs bound, the
+// way Prometheus histograms are exposed.
+type DurationHistogram struct {
+	Buckets []int64
+	Count   int64
+	SumMs   int64
+}
+
+type MetricsSnapshot struct {
+	TotalRequests    int64
+	TotalOrders      int64
+	DueOrders        int64
+	OnTimeOrders     int64
+	Duration         DurationHistogram
+	DrinkDuration    map[DrinkType]DurationHistogram
+	PriorityDuration map[Priority]DurationHistogram
+	Transitions      map[OrderState]int64
+}
+
+// EquipmentStats describes how busy the workers of an equipment are and how
+// much of their time was lost to repairs and cleanings. Utilization is the
+// share of their time spent processing steps.
+type EquipmentStats struct {
+	Workers        int
+	BusyWorkers    int
+	QueueDepth     int
+	JobsCompleted  int64
+	WaitTime       time.Duration
+	ProcessingTime time.Duration
+	WorkerBusyTime []time.Duration
+	Utilization    float64
+	Broken         int
+	Cleaning       int
+	Breakdowns     int64
+	Cleanings      int64
+	RepairTime     time.Duration
+	CleaningTime   time.Duration
+}
+
 func NewOrderMetrics() *OrderMetrics {
 	return &OrderMetrics{
-		histogram: metrics.NewHistogram(metrics.NewExpDecaySample(1028, 0.015)),
+		histogram:        metrics.NewHistogram(metrics.NewExpDecaySample(1028, 0.015)),
+		duration:         newDurationHistogram(),
+		drinkDuration:    make(map[DrinkType]*durationHistogram),
+		priorityDuration: make(map[Priority]*durationHistogram),
+		transitions:      make(map[OrderState]int64),
 	}
 }
 
@@ -33,10 +88,57 @@ func (m *OrderMetrics) RecordOrder(res OrderResult) {
 		return
 	}
 
-	duration := res.Steps[len(res.Steps)-1].EndTimeMs -
-		res.Steps[0].StartTimeMs
+	// steps may run in parallel, so the last step is not necessarily the
+	// last one to finish
+	start, end := res.Steps[0].StartTimeMs, res.Steps[0].EndTimeMs
+	for _, step := range res.Steps[1:] {
+		start = min(start, step.StartTimeMs)
+		end = max(end, step.EndTimeMs)
+	}
+
+	m.histogram.Update(end - start)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.duration.observe(end - start)
+
+	observeBy(m.drinkDuration, res.Drink, end-start)
+	observeBy(m.priorityDuration, res.Priority, end-start)
+}
+
+// observeBy observes ms in the histogram of key, adding it on first use.
+// m.mu must be held.
+func observeBy[K comparable](histograms map[K]*durationHistogram, key K, ms int64) {
+	h, ok := histograms[key]
+	if !ok {
+		nh := newDurationHistogram()
+		h = &nh
+		histograms[key] = h
+	}
+	h.observe(ms)
+}
+
+// RecordDeadline counts whether an order with a due time met it. Orders that
+// failed count as late, while cancelled orders and orders without a due time
+// are left out.
+func (m *OrderMetrics) RecordDeadline(res OrderResult) {
+	if res.DueAtMs == 0 || res.Status == OrderStatusCancelled {
+		return
+	}
+
+	atomic.AddInt64(&m.dueOrders, 1)
+	if res.MetDeadline() {
+		atomic.AddInt64(&m.onTimeOrders, 1)
+	}
+}
+
+// RecordTransition counts an order going to a new state of its lifecycle.
+func (m *OrderMetrics) RecordTransition(tr Transition) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	m.histogram.Update(duration)
+	m.transitions[tr.To]++
 }
 
 func (m *OrderMetrics) GetStats() (int64, int64, int64) {
@@ -46,3 +148,72 @@ func (m *OrderMetrics) GetStats() (int64, int64, int64) {
 
 	return totalReq, snap.Count(), int64(snap.Percentile(0.9))
 }
+
+func (m *OrderMetrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snap := MetricsSnapshot{
+		TotalRequests:    atomic.LoadInt64(&m.totalRequests),
+		TotalOrders:      atomic.LoadInt64(&m.totalOrders),
+		DueOrders:        atomic.LoadInt64(&m.dueOrders),
+		OnTimeOrders:     atomic.LoadInt64(&m.onTimeOrders),
+		Duration:         m.duration.snapshot(),
+		DrinkDuration:    make(map[DrinkType]DurationHistogram, len(m.drinkDuration)),
+		PriorityDuration: make(map[Priority]DurationHistogram, len(m.priorityDuration)),
+		Transitions:      maps.Clone(m.transitions),
+	}
+	for drink, h := range m.drinkDuration {
+		snap.DrinkDuration[drink] = h.snapshot()
+	}
+	for priority, h := range m.priorityDuration {
+		snap.PriorityDuration[priority] = h.snapshot()
+	}
+
+	return snap
+}
+
+// OnTimePercent is the share of the orders with a due time that met it, in
+// percent. It is 100 until an order has a due time.
+func (s MetricsSnapshot) OnTimePercent() float64 {
+	if s.DueOrders == 0 {
+		return 100
+	}
+
+	return 100 * float64(s.OnTimeOrders) / float64(s.DueOrders)
+}
+
+type durationHistogram struct {
+	counts []int64 // per bucket, the last one being +Inf
+	sum    int64
+}
+
+func newDurationHistogram() durationHistogram {
+	return durationHistogram{
+		counts: make([]int64, len(DurationBucketsMs)+1),
+	}
+}
+
+func (h *durationHistogram) observe(ms int64) {
+	i := 0
+	for i < len(DurationBucketsMs) && ms > DurationBucketsMs[i] {
+		i++
+	}
+	h.counts[i]++
+	h.sum += ms
+}
+
+func (h *durationHistogram) snapshot() DurationHistogram {
+	snap := DurationHistogram{
+		Buckets: make([]int64, len(DurationBucketsMs)),
+		SumMs:   h.sum,
+	}
+	for i, count := range h.counts {
+		snap.Count += count
+		if i < len(snap.Buckets) {
+			snap.Buckets[i] = snap.Count
+		}
+	}
+
+	return snap
+}
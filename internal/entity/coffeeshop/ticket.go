@@ -0,0 +1,33 @@
+package coffeeshop
+
+// OrderProgress is how far an order of a ticket got. CurrentSteps are the
+// steps started and not finished yet, by recipe index, which may be several
+// as the independent steps of a recipe run side by side. Result is set once
+// the order is brewed.
+type OrderProgress struct {
+	Order        Order
+	Lifecycle    Lifecycle
+	CurrentSteps []StepExecution
+	StepsDone    int
+	StepsTotal   int
+	Result       OrderResult
+}
+
+// Ticket follows the orders submitted together to be brewed in the
+// background.
+type Ticket struct {
+	ID          string
+	CreatedAtMs int64
+	Orders      []OrderProgress
+}
+
+// Done tells whether every order of the ticket is brewed.
+func (t Ticket) Done() bool {
+	for _, o := range t.Orders {
+		if !o.Lifecycle.State().Brewed() {
+			return false
+		}
+	}
+
+	return true
+}
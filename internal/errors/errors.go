@@ -1 +1,44 @@
 package errors
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrPoolClosed          = errors.New("pool closed")
+	ErrJobCancelled        = errors.New("job cancelled")
+	ErrNoWorkerPool        = errors.New("no worker pool registered")
+	ErrUnknownRecipe       = errors.New("unknown recipe")
+	ErrBrokenDown          = errors.New("equipment broken down")
+	ErrOutOfStock          = errors.New("out of stock")
+	ErrNoIngredient        = errors.New("unknown ingredient")
+	ErrBadModifiers        = errors.New("modifiers do not apply")
+	ErrNoTicket            = errors.New("unknown ticket")
+	ErrIllegalTransition   = errors.New("illegal order state transition")
+	ErrDeadlineUnreachable = errors.New("cannot finish before the deadline")
+	ErrRateLimited         = errors.New("rate limit exceeded")
+	ErrOverloaded          = errors.New("too many brews in flight")
+	ErrTooLarge            = errors.New("request too large")
+)
+
+// ValidationError lists every invalid field of a request at once, so that
+// clients do not have to fix them one round trip at a time.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Violation tells why a field, e.g. "orders[2].drink", is invalid.
+type Violation struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Field + ": " + v.Reason
+	}
+
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
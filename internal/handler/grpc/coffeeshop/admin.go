@@ -0,0 +1,118 @@
+//go:generate go tool mockgen -source=$GOFILE -destination=mock_admin_test.go -package=$GOPACKAGE
+package coffeeshop
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"math"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+
+	"github.com/ajaibid/coin-common-golang/logger"
+
+	cafepb "gopher-cafe/pkg/gen/go/cafe/v1"
+)
+
+type AdminUsecase interface {
+	ResizeEquipment(equipType entity.EquipmentType, workers uint8) (uint8, error)
+	Restock(ingredient entity.Ingredient, quantity int64) (entity.IngredientLevel, error)
+	GetInventory() []entity.IngredientLevel
+	GetEquipmentStats() map[entity.EquipmentType]entity.EquipmentStats
+}
+
+// AdminGrpcHandler implements the cafepb.CafeAdminServiceServer interface
+type AdminGrpcHandler struct {
+	cafepb.UnimplementedCafeAdminServiceServer
+	uc AdminUsecase
+}
+
+func NewAdminGrpcHandler(uc AdminUsecase) *AdminGrpcHandler {
+	return &AdminGrpcHandler{
+		uc: uc,
+	}
+}
+
+// ResizeEquipment grows or shrinks the worker pool of an equipment, e.g. to add
+// a second grinder during a rush.
+func (h *AdminGrpcHandler) ResizeEquipment(ctx context.Context, req *cafepb.ResizeEquipmentRequest) (*cafepb.ResizeEquipmentResponse, error) {
+	logger.Infof("Incoming admin request: %+v", req)
+
+	equipType, ok := toEntityEquipment(req.Equipment)
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unspecified or unknown equipment")
+	}
+	if req.Workers < 1 || req.Workers > math.MaxUint8 {
+		return nil, status.Errorf(codes.InvalidArgument, "workers must be between 1 and %d", math.MaxUint8)
+	}
+
+	previous, err := h.uc.ResizeEquipment(equipType, uint8(req.Workers))
+	if errors.Is(err, apperrors.ErrNoWorkerPool) {
+		return nil, status.Errorf(codes.NotFound, "%s has no worker pool", equipType)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &cafepb.ResizeEquipmentResponse{
+		Equipment:       req.Equipment,
+		PreviousWorkers: uint32(previous),
+		Workers:         req.Workers,
+	}, nil
+}
+
+// Restock adds to the stock of an ingredient already in the inventory.
+func (h *AdminGrpcHandler) Restock(ctx context.Context, req *cafepb.RestockRequest) (*cafepb.RestockResponse, error) {
+	logger.Infof("Incoming admin request: %+v", req)
+
+	if req.Ingredient == "" {
+		return nil, status.Error(codes.InvalidArgument, "ingredient is required")
+	}
+	if req.Quantity <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+	}
+
+	level, err := h.uc.Restock(entity.Ingredient(req.Ingredient), req.Quantity)
+	if errors.Is(err, apperrors.ErrNoIngredient) {
+		return nil, status.Errorf(codes.NotFound, "%s is not in the inventory", req.Ingredient)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &cafepb.RestockResponse{Level: toCafePbIngredientLevel(level)}, nil
+}
+
+// GetInventory returns the stock of every ingredient by name.
+func (h *AdminGrpcHandler) GetInventory(ctx context.Context, req *cafepb.GetInventoryRequest) (*cafepb.GetInventoryResponse, error) {
+	levels := h.uc.GetInventory()
+
+	resp := &cafepb.GetInventoryResponse{
+		Ingredients: make([]*cafepb.IngredientLevel, len(levels)),
+	}
+	for i, level := range levels {
+		resp.Ingredients[i] = toCafePbIngredientLevel(level)
+	}
+
+	return resp, nil
+}
+
+// GetEquipmentStats returns the utilization of every equipment and how long
+// its steps waited for a worker against how long they were processed.
+func (h *AdminGrpcHandler) GetEquipmentStats(ctx context.Context, req *cafepb.GetEquipmentStatsRequest) (*cafepb.GetEquipmentStatsResponse, error) {
+	stats := h.uc.GetEquipmentStats()
+
+	resp := &cafepb.GetEquipmentStatsResponse{
+		Equipment: make([]*cafepb.EquipmentStats, 0, len(stats)),
+	}
+	for _, equipType := range slices.Sorted(maps.Keys(stats)) {
+		resp.Equipment = append(resp.Equipment, toCafePbEquipmentStats(equipType, stats[equipType]))
+	}
+
+	return resp, nil
+}
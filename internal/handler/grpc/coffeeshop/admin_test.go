@@ -0,0 +1,256 @@
+package coffeeshop
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+
+	cafepb "gopher-cafe/pkg/gen/go/cafe/v1"
+
+	pb "github.com/rexyajaib/gopher-cafe/pkg/gen/go/v1"
+)
+
+func TestAdminResizeEquipment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockAdminUsecase(ctrl)
+	handler := NewAdminGrpcHandler(mockUC)
+
+	ctx := t.Context()
+
+	tests := []struct {
+		name         string
+		req          *cafepb.ResizeEquipmentRequest
+		mockExpect   func()
+		expectedCode codes.Code
+		expectedRes  *cafepb.ResizeEquipmentResponse
+	}{
+		{
+			name: "Success - Second Grinder",
+			req:  &cafepb.ResizeEquipmentRequest{Equipment: pb.EquipmentType_EQUIPMENT_TYPE_GRINDER, Workers: 2},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					ResizeEquipment(entity.EquipGrinder, uint8(2)).
+					Return(uint8(1), nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes: &cafepb.ResizeEquipmentResponse{
+				Equipment:       pb.EquipmentType_EQUIPMENT_TYPE_GRINDER,
+				PreviousWorkers: 1,
+				Workers:         2,
+			},
+		},
+		{
+			name:         "Error - Unspecified Equipment",
+			req:          &cafepb.ResizeEquipmentRequest{Workers: 2},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Error - No Workers",
+			req:          &cafepb.ResizeEquipmentRequest{Equipment: pb.EquipmentType_EQUIPMENT_TYPE_WHISK},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Error - Too Many Workers",
+			req:          &cafepb.ResizeEquipmentRequest{Equipment: pb.EquipmentType_EQUIPMENT_TYPE_WHISK, Workers: 256},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Error - No Pool",
+			req:  &cafepb.ResizeEquipmentRequest{Equipment: pb.EquipmentType_EQUIPMENT_TYPE_BLENDER, Workers: 1},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					ResizeEquipment(entity.EquipBlender, uint8(1)).
+					Return(uint8(0), apperrors.ErrNoWorkerPool)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "Error - Resize Failed",
+			req:  &cafepb.ResizeEquipmentRequest{Equipment: pb.EquipmentType_EQUIPMENT_TYPE_BLENDER, Workers: 1},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					ResizeEquipment(entity.EquipBlender, uint8(1)).
+					Return(uint8(0), errors.New("boom"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockExpect()
+
+			resp, err := handler.ResizeEquipment(ctx, tt.req)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.True(t, proto.Equal(tt.expectedRes, resp), "got %v", resp)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+		})
+	}
+}
+
+func TestAdminRestock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockAdminUsecase(ctrl)
+	handler := NewAdminGrpcHandler(mockUC)
+
+	ctx := t.Context()
+
+	tests := []struct {
+		name         string
+		req          *cafepb.RestockRequest
+		mockExpect   func()
+		expectedCode codes.Code
+		expectedRes  *cafepb.RestockResponse
+	}{
+		{
+			name: "Success",
+			req:  &cafepb.RestockRequest{Ingredient: "milk", Quantity: 500},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					Restock(entity.IngredientMilk, int64(500)).
+					Return(entity.IngredientLevel{Ingredient: entity.IngredientMilk, Quantity: 700, LowStock: 100}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes: &cafepb.RestockResponse{
+				Level: &cafepb.IngredientLevel{Ingredient: "milk", Quantity: 700, LowStock: 100},
+			},
+		},
+		{
+			name:         "Error - No Ingredient",
+			req:          &cafepb.RestockRequest{Quantity: 500},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Error - Negative Quantity",
+			req:          &cafepb.RestockRequest{Ingredient: "milk", Quantity: -1},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Error - Not In The Inventory",
+			req:  &cafepb.RestockRequest{Ingredient: "oat_milk", Quantity: 500},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					Restock(entity.Ingredient("oat_milk"), int64(500)).
+					Return(entity.IngredientLevel{}, apperrors.ErrNoIngredient)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockExpect()
+
+			resp, err := handler.Restock(ctx, tt.req)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.True(t, proto.Equal(tt.expectedRes, resp), "got %v", resp)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+		})
+	}
+}
+
+func TestAdminGetInventory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockAdminUsecase(ctrl)
+	handler := NewAdminGrpcHandler(mockUC)
+
+	mockUC.EXPECT().GetInventory().Return([]entity.IngredientLevel{
+		{Ingredient: entity.IngredientBeans, Quantity: 400, LowStock: 500},
+		{Ingredient: entity.IngredientMilk, Quantity: 9000, LowStock: 1000},
+	})
+
+	resp, err := handler.GetInventory(t.Context(), &cafepb.GetInventoryRequest{})
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(&cafepb.GetInventoryResponse{
+		Ingredients: []*cafepb.IngredientLevel{
+			{Ingredient: "beans", Quantity: 400, LowStock: 500},
+			{Ingredient: "milk", Quantity: 9000, LowStock: 1000},
+		},
+	}, resp), "got %v", resp)
+}
+
+func TestAdminGetEquipmentStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockAdminUsecase(ctrl)
+	handler := NewAdminGrpcHandler(mockUC)
+
+	mockUC.EXPECT().GetEquipmentStats().Return(map[entity.EquipmentType]entity.EquipmentStats{
+		entity.EquipEspressoMachine: {Workers: 2, JobsCompleted: 1, ProcessingTime: 8 * time.Millisecond, WorkerBusyTime: []time.Duration{8 * time.Millisecond, 0}, Utilization: 0.25},
+		entity.EquipGrinder: {
+			Workers:        1,
+			BusyWorkers:    1,
+			QueueDepth:     3,
+			JobsCompleted:  4,
+			WaitTime:       30 * time.Millisecond,
+			ProcessingTime: 20 * time.Millisecond,
+			WorkerBusyTime: []time.Duration{20 * time.Millisecond},
+			Utilization:    1,
+			Cleaning:       1,
+			Cleanings:      2,
+			CleaningTime:   10 * time.Millisecond,
+		},
+	})
+
+	resp, err := handler.GetEquipmentStats(t.Context(), &cafepb.GetEquipmentStatsRequest{})
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(&cafepb.GetEquipmentStatsResponse{
+		Equipment: []*cafepb.EquipmentStats{
+			{
+				Equipment:       pb.EquipmentType_EQUIPMENT_TYPE_GRINDER,
+				Workers:         1,
+				BusyWorkers:     1,
+				QueueDepth:      3,
+				JobsCompleted:   4,
+				WaitMs:          30,
+				ProcessingMs:    20,
+				WorkerBusyMs:    []int64{20},
+				Utilization:     1,
+				CleaningWorkers: 1,
+				Cleanings:       2,
+				CleaningMs:      10,
+			},
+			{
+				Equipment:     pb.EquipmentType_EQUIPMENT_TYPE_ESPRESSO_MACHINE,
+				Workers:       2,
+				JobsCompleted: 1,
+				ProcessingMs:  8,
+				WorkerBusyMs:  []int64{8, 0},
+				Utilization:   0.25,
+			},
+		},
+	}, resp), "got %v", resp)
+}
@@ -0,0 +1,150 @@
+package coffeeshop
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+
+	"github.com/ajaibid/coin-common-golang/logger"
+
+	cafepb "gopher-cafe/pkg/gen/go/cafe/v1"
+)
+
+// CafeGrpcHandler implements the cafepb.CafeServiceServer interface
+type CafeGrpcHandler struct {
+	cafepb.UnimplementedCafeServiceServer
+	uc CoffeeshopUsecase
+}
+
+func NewCafeGrpcHandler(uc CoffeeshopUsecase) *CafeGrpcHandler {
+	return &CafeGrpcHandler{
+		uc: uc,
+	}
+}
+
+// ExecuteBrew runs the same simulation as CoffeeshopGrpcHandler.ExecuteBrew
+// and reports when each step waited for and used its equipment.
+func (h *CafeGrpcHandler) ExecuteBrew(ctx context.Context, req *cafepb.ExecuteBrewRequest) (*cafepb.ExecuteBrewResponse, error) {
+	logger.Infof("Incoming request: %+v", req)
+	internalOrders, strategy, err := brewInput(ctx, req.Baristas, req.Orders)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := h.uc.ExecuteBrew(ctx, internalOrders, int(req.Baristas), strategy)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoResults := make([]*cafepb.Result, len(results))
+	for i, res := range results {
+		protoResults[i] = toCafePbResult(res)
+	}
+
+	return &cafepb.ExecuteBrewResponse{
+		Results: protoResults,
+	}, nil
+}
+
+// StreamBrew runs the simulation like ExecuteBrew and sends an event each time
+// a step starts or finishes and each time an order completes.
+func (h *CafeGrpcHandler) StreamBrew(req *cafepb.ExecuteBrewRequest, stream cafepb.CafeService_StreamBrewServer) error {
+	logger.Infof("Incoming stream request: %+v", req)
+	internalOrders, strategy, err := brewInput(stream.Context(), req.Baristas, req.Orders)
+	if err != nil {
+		return err
+	}
+
+	// stop brewing once the client can no longer be reached
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	var sendErr error
+	_, err = h.uc.StreamBrew(ctx, internalOrders, int(req.Baristas), strategy, func(ev entity.BrewEvent) {
+		if sendErr != nil {
+			return
+		}
+		if sendErr = stream.Send(toCafePbEvent(ev)); sendErr != nil {
+			logger.Errorf("Sending brew event failed: %s", sendErr)
+			cancel()
+		}
+	})
+	if err != nil {
+		return toStatusError(err)
+	}
+
+	return sendErr
+}
+
+// SubmitOrders checks the orders like ExecuteBrew and returns a ticket right
+// away, leaving the brew to run past the deadline of the request.
+func (h *CafeGrpcHandler) SubmitOrders(ctx context.Context, req *cafepb.ExecuteBrewRequest) (*cafepb.SubmitOrdersResponse, error) {
+	logger.Infof("Incoming submit request: %+v", req)
+	internalOrders, strategy, err := brewInput(ctx, req.Baristas, req.Orders)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := h.uc.SubmitOrders(ctx, internalOrders, int(req.Baristas), strategy)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &cafepb.SubmitOrdersResponse{
+		TicketId: ticket.ID,
+	}, nil
+}
+
+// GetOrderStatus returns the orders of a ticket, queued, in progress with the
+// steps they are on, or over with their result.
+func (h *CafeGrpcHandler) GetOrderStatus(ctx context.Context, req *cafepb.GetOrderStatusRequest) (*cafepb.GetOrderStatusResponse, error) {
+	if req.TicketId == "" {
+		return nil, status.Error(codes.InvalidArgument, "ticket_id is required")
+	}
+
+	ticket, err := h.uc.GetOrderStatus(req.TicketId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &cafepb.GetOrderStatusResponse{
+		Ticket: toCafePbTicket(ticket),
+	}, nil
+}
+
+// CancelOrder stops brewing the orders of a ticket that are not brewed yet.
+func (h *CafeGrpcHandler) CancelOrder(ctx context.Context, req *cafepb.CancelOrderRequest) (*cafepb.CancelOrderResponse, error) {
+	logger.Infof("Incoming cancel request: %+v", req)
+	if req.TicketId == "" {
+		return nil, status.Error(codes.InvalidArgument, "ticket_id is required")
+	}
+
+	ticket, err := h.uc.CancelOrder(ctx, req.TicketId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &cafepb.CancelOrderResponse{
+		Ticket: toCafePbTicket(ticket),
+	}, nil
+}
+
+// PickUpOrder hands over the ready orders of a ticket.
+func (h *CafeGrpcHandler) PickUpOrder(ctx context.Context, req *cafepb.PickUpOrderRequest) (*cafepb.PickUpOrderResponse, error) {
+	logger.Infof("Incoming pick up request: %+v", req)
+	if req.TicketId == "" {
+		return nil, status.Error(codes.InvalidArgument, "ticket_id is required")
+	}
+
+	ticket, err := h.uc.PickUpOrder(ctx, req.TicketId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &cafepb.PickUpOrderResponse{
+		Ticket: toCafePbTicket(ticket),
+	}, nil
+}
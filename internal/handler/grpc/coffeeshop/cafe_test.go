@@ -0,0 +1,814 @@
+package coffeeshop
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+
+	cafepb "gopher-cafe/pkg/gen/go/cafe/v1"
+
+	pb "github.com/rexyajaib/gopher-cafe/pkg/gen/go/v1"
+)
+
+func TestCafeExecuteBrew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockCoffeeshopUsecase(ctrl)
+	handler := NewCafeGrpcHandler(mockUC)
+
+	ctx := t.Context()
+
+	tests := []struct {
+		name         string
+		req          *cafepb.ExecuteBrewRequest
+		mockExpect   func()
+		expectedCode codes.Code
+		expectedRes  *cafepb.ExecuteBrewResponse
+	}{
+		{
+			name: "Success - Step Timeline",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders: []*cafepb.Order{
+					{Id: 101, Drink: pb.DrinkType_DRINK_TYPE_ESPRESSO},
+				},
+			},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					ExecuteBrew(ctx, gomock.Len(1), 1, entity.SchedulingStrategy("")).
+					Return([]entity.OrderResult{
+						{
+							OrderID: 101,
+							Drink:   entity.DrinkEspresso,
+							Status:  entity.OrderStatusCompleted,
+							Steps: []entity.StepExecution{
+								{
+									Equipment:    entity.EquipGrinder,
+									StartTimeMs:  10,
+									EndTimeMs:    20,
+									QueuedAtMs:   10,
+									AcquiredAtMs: 15,
+									ReleasedAtMs: 20,
+									WorkerID:     1,
+								},
+							},
+						},
+					}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes: &cafepb.ExecuteBrewResponse{
+				Results: []*cafepb.Result{
+					{
+						OrderId:   101,
+						Drink:     pb.DrinkType_DRINK_TYPE_ESPRESSO,
+						DrinkName: "espresso",
+						Status:    cafepb.OrderStatus_ORDER_STATUS_COMPLETED,
+						Steps: []*cafepb.Step{
+							{
+								Equipment:    pb.EquipmentType_EQUIPMENT_TYPE_GRINDER,
+								StartMs:      10,
+								EndMs:        20,
+								QueuedAtMs:   10,
+								AcquiredAtMs: 15,
+								ReleasedAtMs: 20,
+								WorkerId:     1,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Success - Failed Order Reported",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders: []*cafepb.Order{
+					{Id: 102, Drink: pb.DrinkType_DRINK_TYPE_LATTE},
+				},
+			},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					ExecuteBrew(ctx, gomock.Len(1), 1, entity.SchedulingStrategy("")).
+					Return([]entity.OrderResult{
+						{
+							OrderID: 102,
+							Drink:   entity.DrinkLatte,
+							Status:  entity.OrderStatusFailed,
+							Error:   "pool closed",
+						},
+					}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes: &cafepb.ExecuteBrewResponse{
+				Results: []*cafepb.Result{
+					{
+						OrderId:   102,
+						Drink:     pb.DrinkType_DRINK_TYPE_LATTE,
+						DrinkName: "latte",
+						Status:    cafepb.OrderStatus_ORDER_STATUS_FAILED,
+						Error:     "pool closed",
+						Steps:     []*cafepb.Step{},
+					},
+				},
+			},
+		},
+		{
+			name: "Success - Drink By Name",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders: []*cafepb.Order{
+					{Id: 103, DrinkName: "iced_latte"},
+				},
+			},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					ExecuteBrew(ctx, []entity.Order{{ID: 103, Drink: "iced_latte"}}, 1, entity.SchedulingStrategy("")).
+					Return([]entity.OrderResult{
+						{
+							OrderID: 103,
+							Drink:   "iced_latte",
+							Status:  entity.OrderStatusCompleted,
+						},
+					}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes: &cafepb.ExecuteBrewResponse{
+				Results: []*cafepb.Result{
+					{
+						OrderId:   103,
+						Drink:     pb.DrinkType_DRINK_TYPE_UNSPECIFIED,
+						DrinkName: "iced_latte",
+						Status:    cafepb.OrderStatus_ORDER_STATUS_COMPLETED,
+						Steps:     []*cafepb.Step{},
+					},
+				},
+			},
+		},
+		{
+			name: "Success - Modifiers",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders: []*cafepb.Order{
+					{Id: 104, Drink: pb.DrinkType_DRINK_TYPE_LATTE, Modifiers: &cafepb.Modifiers{
+						Size:       cafepb.Size_SIZE_LARGE,
+						ExtraShots: 1,
+						Milk:       cafepb.Milk_MILK_OAT,
+						Iced:       true,
+					}},
+				},
+			},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					ExecuteBrew(ctx, []entity.Order{{ID: 104, Drink: entity.DrinkLatte, Modifiers: entity.Modifiers{
+						Size:       entity.SizeLarge,
+						ExtraShots: 1,
+						Milk:       entity.MilkOat,
+						Iced:       true,
+					}}}, 1, entity.SchedulingStrategy("")).
+					Return([]entity.OrderResult{
+						{
+							OrderID: 104,
+							Drink:   entity.DrinkLatte,
+							Status:  entity.OrderStatusCompleted,
+						},
+					}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes: &cafepb.ExecuteBrewResponse{
+				Results: []*cafepb.Result{
+					{
+						OrderId:   104,
+						Drink:     pb.DrinkType_DRINK_TYPE_LATTE,
+						DrinkName: "latte",
+						Status:    cafepb.OrderStatus_ORDER_STATUS_COMPLETED,
+						Steps:     []*cafepb.Step{},
+					},
+				},
+			},
+		},
+		{
+			name: "Success - Due Time",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders:   []*cafepb.Order{{Id: 105, Drink: pb.DrinkType_DRINK_TYPE_ESPRESSO, DueAtMs: 1000}},
+			},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					ExecuteBrew(ctx, []entity.Order{{ID: 105, Drink: entity.DrinkEspresso, DueAtMs: 1000}}, 1, entity.SchedulingStrategy("")).
+					Return([]entity.OrderResult{
+						{
+							OrderID: 105,
+							Drink:   entity.DrinkEspresso,
+							Status:  entity.OrderStatusCompleted,
+							Steps: []entity.StepExecution{
+								{Equipment: entity.EquipGrinder, StartTimeMs: 900, EndTimeMs: 980},
+							},
+							DueAtMs:    1000,
+							LatenessMs: -20,
+						},
+					}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes: &cafepb.ExecuteBrewResponse{
+				Results: []*cafepb.Result{
+					{
+						OrderId:   105,
+						Drink:     pb.DrinkType_DRINK_TYPE_ESPRESSO,
+						DrinkName: "espresso",
+						Status:    cafepb.OrderStatus_ORDER_STATUS_COMPLETED,
+						Steps: []*cafepb.Step{
+							{Equipment: pb.EquipmentType_EQUIPMENT_TYPE_GRINDER, StartMs: 900, EndMs: 980},
+						},
+						DueAtMs:     1000,
+						LatenessMs:  -20,
+						MetDeadline: true,
+					},
+				},
+			},
+		},
+		{
+			name: "Success - Priority",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders:   []*cafepb.Order{{Id: 106, Drink: pb.DrinkType_DRINK_TYPE_ESPRESSO, Priority: cafepb.Priority_PRIORITY_VIP}},
+			},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					ExecuteBrew(ctx, []entity.Order{{ID: 106, Drink: entity.DrinkEspresso, Priority: entity.PriorityVIP}}, 1, entity.SchedulingStrategy("")).
+					Return([]entity.OrderResult{
+						{
+							OrderID:  106,
+							Drink:    entity.DrinkEspresso,
+							Priority: entity.PriorityVIP,
+							Status:   entity.OrderStatusCompleted,
+						},
+					}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes: &cafepb.ExecuteBrewResponse{
+				Results: []*cafepb.Result{
+					{
+						OrderId:   106,
+						Drink:     pb.DrinkType_DRINK_TYPE_ESPRESSO,
+						DrinkName: "espresso",
+						Status:    cafepb.OrderStatus_ORDER_STATUS_COMPLETED,
+						Steps:     []*cafepb.Step{},
+						Priority:  cafepb.Priority_PRIORITY_VIP,
+					},
+				},
+			},
+		},
+		{
+			name: "Error - Unknown Size",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders:   []*cafepb.Order{{Id: 1, Drink: pb.DrinkType_DRINK_TYPE_LATTE, Modifiers: &cafepb.Modifiers{Size: 9}}},
+			},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Error - Too Many Shots",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders:   []*cafepb.Order{{Id: 1, Drink: pb.DrinkType_DRINK_TYPE_LATTE, Modifiers: &cafepb.Modifiers{ExtraShots: 4}}},
+			},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Error - Rejected By Usecase",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders:   []*cafepb.Order{{Id: 1, Drink: pb.DrinkType_DRINK_TYPE_ESPRESSO}},
+			},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					ExecuteBrew(ctx, gomock.Len(1), 1, entity.SchedulingStrategy("")).
+					Return(nil, &apperrors.ValidationError{Violations: []apperrors.Violation{
+						{Field: "orders[0].drink", Reason: "unspecified or unknown drink"},
+					}})
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Error - Invalid Baristas",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 0,
+				Orders:   []*cafepb.Order{{Id: 1, Drink: pb.DrinkType_DRINK_TYPE_ESPRESSO}},
+			},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Error - Invalid Order ID",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders:   []*cafepb.Order{{Id: -1, Drink: pb.DrinkType_DRINK_TYPE_ESPRESSO}},
+			},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockExpect()
+
+			resp, err := handler.ExecuteBrew(ctx, tt.req)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.True(t, proto.Equal(tt.expectedRes, resp), "got %v", resp)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+		})
+	}
+}
+
+// fakeBrewStream records the events sent to it, failing from the failAt-th
+// one on when failAt is set.
+type fakeBrewStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	failAt int
+	events []cafepb.BrewEventType
+}
+
+func (s *fakeBrewStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeBrewStream) Send(ev *cafepb.BrewEvent) error {
+	if s.failAt > 0 && len(s.events)+1 >= s.failAt {
+		return errors.New("stream closed")
+	}
+	s.events = append(s.events, ev.Type)
+
+	return nil
+}
+
+func TestCafeStreamBrew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockCoffeeshopUsecase(ctrl)
+	handler := NewCafeGrpcHandler(mockUC)
+
+	req := &cafepb.ExecuteBrewRequest{
+		Baristas: 1,
+		Orders:   []*cafepb.Order{{Id: 1, Drink: pb.DrinkType_DRINK_TYPE_ESPRESSO}},
+	}
+	events := []entity.BrewEvent{
+		{Type: entity.BrewEventStepStarted, OrderID: 1},
+		{Type: entity.BrewEventStepFinished, OrderID: 1},
+		{Type: entity.BrewEventOrderCompleted, OrderID: 1},
+	}
+
+	tests := []struct {
+		name         string
+		failAt       int
+		brewErr      error
+		expectedCode codes.Code
+		wantEvents   []cafepb.BrewEventType
+		wantCanceled bool
+	}{
+		{
+			name:         "Success",
+			expectedCode: codes.OK,
+			wantEvents: []cafepb.BrewEventType{
+				cafepb.BrewEventType_BREW_EVENT_TYPE_STEP_STARTED,
+				cafepb.BrewEventType_BREW_EVENT_TYPE_STEP_FINISHED,
+				cafepb.BrewEventType_BREW_EVENT_TYPE_ORDER_COMPLETED,
+			},
+		},
+		{
+			name:         "Error - Send Cancels The Brew",
+			failAt:       2,
+			expectedCode: codes.Unknown,
+			wantEvents:   []cafepb.BrewEventType{cafepb.BrewEventType_BREW_EVENT_TYPE_STEP_STARTED},
+			wantCanceled: true,
+		},
+		{
+			name:         "Error - Rejected By Usecase",
+			brewErr:      &apperrors.ValidationError{Violations: []apperrors.Violation{{Field: "orders[0].drink", Reason: "mocha is not on the menu"}}},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &fakeBrewStream{ctx: t.Context(), failAt: tt.failAt}
+
+			var canceled bool
+			mockUC.EXPECT().
+				StreamBrew(gomock.Any(), []entity.Order{{ID: 1, Drink: entity.DrinkEspresso}}, 1, entity.SchedulingStrategy(""), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, _ []entity.Order, _ int, _ entity.SchedulingStrategy, onEvent func(entity.BrewEvent)) ([]entity.OrderResult, error) {
+					if tt.brewErr != nil {
+						return nil, tt.brewErr
+					}
+					for _, ev := range events {
+						onEvent(ev)
+					}
+					canceled = ctx.Err() != nil
+					return nil, nil
+				})
+
+			err := handler.StreamBrew(req, stream)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.wantEvents, stream.events)
+			assert.Equal(t, tt.wantCanceled, canceled)
+		})
+	}
+}
+
+func TestCafeSubmitOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockCoffeeshopUsecase(ctrl)
+	handler := NewCafeGrpcHandler(mockUC)
+
+	ctx := t.Context()
+
+	tests := []struct {
+		name         string
+		req          *cafepb.ExecuteBrewRequest
+		mockExpect   func()
+		expectedCode codes.Code
+		expectedRes  *cafepb.SubmitOrdersResponse
+	}{
+		{
+			name: "Success",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 2,
+				Orders:   []*cafepb.Order{{Id: 1, Drink: pb.DrinkType_DRINK_TYPE_LATTE}},
+			},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					SubmitOrders(ctx, []entity.Order{{ID: 1, Drink: entity.DrinkLatte}}, 2, entity.SchedulingStrategy("")).
+					Return(entity.Ticket{ID: "T1"}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes:  &cafepb.SubmitOrdersResponse{TicketId: "T1"},
+		},
+		{
+			name: "Error - Rejected By Usecase",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders:   []*cafepb.Order{{Id: 1, DrinkName: "mocha"}},
+			},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					SubmitOrders(ctx, gomock.Len(1), 1, entity.SchedulingStrategy("")).
+					Return(entity.Ticket{}, &apperrors.ValidationError{Violations: []apperrors.Violation{
+						{Field: "orders[0].drink", Reason: "mocha is not on the menu"},
+					}})
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Error - Invalid Baristas",
+			req: &cafepb.ExecuteBrewRequest{
+				Baristas: 0,
+				Orders:   []*cafepb.Order{{Id: 1, Drink: pb.DrinkType_DRINK_TYPE_ESPRESSO}},
+			},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockExpect()
+
+			resp, err := handler.SubmitOrders(ctx, tt.req)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.True(t, proto.Equal(tt.expectedRes, resp), "got %v", resp)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+		})
+	}
+}
+
+func TestCafeGetOrderStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockCoffeeshopUsecase(ctrl)
+	handler := NewCafeGrpcHandler(mockUC)
+
+	ctx := t.Context()
+
+	tests := []struct {
+		name         string
+		req          *cafepb.GetOrderStatusRequest
+		mockExpect   func()
+		expectedCode codes.Code
+		expectedRes  *cafepb.GetOrderStatusResponse
+	}{
+		{
+			name: "Success",
+			req:  &cafepb.GetOrderStatusRequest{TicketId: "T1"},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					GetOrderStatus("T1").
+					Return(entity.Ticket{
+						ID:          "T1",
+						CreatedAtMs: 100,
+						Orders: []entity.OrderProgress{
+							{
+								Order: entity.Order{ID: 1, Drink: entity.DrinkLatte},
+								Lifecycle: entity.Lifecycle{Transitions: []entity.Transition{
+									{To: entity.OrderStateReceived, AtMs: 100},
+									{From: entity.OrderStateReceived, To: entity.OrderStateQueued, AtMs: 101},
+									{From: entity.OrderStateQueued, To: entity.OrderStateBrewing, Step: 2, AtMs: 110},
+								}},
+								CurrentSteps: []entity.StepExecution{{Equipment: entity.EquipMilkSteamer, StartTimeMs: 110}},
+								StepsDone:    1,
+								StepsTotal:   3,
+							},
+							{
+								Order: entity.Order{ID: 2, Drink: entity.DrinkEspresso},
+								Lifecycle: entity.Lifecycle{Transitions: []entity.Transition{
+									{To: entity.OrderStateReceived, AtMs: 100},
+									{From: entity.OrderStateReceived, To: entity.OrderStateFailed, AtMs: 100},
+								}},
+								StepsTotal: 2,
+								Result:     entity.OrderResult{OrderID: 2, Drink: entity.DrinkEspresso, Status: entity.OrderStatusFailed, Error: "out of stock"},
+							},
+						},
+					}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes: &cafepb.GetOrderStatusResponse{
+				Ticket: &cafepb.Ticket{
+					Id:          "T1",
+					CreatedAtMs: 100,
+					Orders: []*cafepb.OrderProgress{
+						{
+							OrderId:      1,
+							DrinkName:    "latte",
+							State:        cafepb.OrderState_ORDER_STATE_BREWING,
+							CurrentSteps: []*cafepb.Step{{Equipment: pb.EquipmentType_EQUIPMENT_TYPE_MILK_STEAMER, StartMs: 110}},
+							StepsDone:    1,
+							StepsTotal:   3,
+							Transitions: []*cafepb.Transition{
+								{To: cafepb.OrderState_ORDER_STATE_RECEIVED, AtMs: 100},
+								{From: cafepb.OrderState_ORDER_STATE_RECEIVED, To: cafepb.OrderState_ORDER_STATE_QUEUED, AtMs: 101},
+								{From: cafepb.OrderState_ORDER_STATE_QUEUED, To: cafepb.OrderState_ORDER_STATE_BREWING, Step: 2, AtMs: 110},
+							},
+						},
+						{
+							OrderId:      2,
+							DrinkName:    "espresso",
+							State:        cafepb.OrderState_ORDER_STATE_FAILED,
+							CurrentSteps: []*cafepb.Step{},
+							StepsTotal:   2,
+							Transitions: []*cafepb.Transition{
+								{To: cafepb.OrderState_ORDER_STATE_RECEIVED, AtMs: 100},
+								{From: cafepb.OrderState_ORDER_STATE_RECEIVED, To: cafepb.OrderState_ORDER_STATE_FAILED, AtMs: 100},
+							},
+							Result: &cafepb.Result{
+								OrderId:   2,
+								Drink:     pb.DrinkType_DRINK_TYPE_ESPRESSO,
+								DrinkName: "espresso",
+								Status:    cafepb.OrderStatus_ORDER_STATUS_FAILED,
+								Error:     "out of stock",
+								Steps:     []*cafepb.Step{},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Error - Unknown Ticket",
+			req:  &cafepb.GetOrderStatusRequest{TicketId: "T2"},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					GetOrderStatus("T2").
+					Return(entity.Ticket{}, apperrors.ErrNoTicket)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "Error - Missing Ticket ID",
+			req:          &cafepb.GetOrderStatusRequest{},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockExpect()
+
+			resp, err := handler.GetOrderStatus(ctx, tt.req)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.True(t, proto.Equal(tt.expectedRes, resp), "got %v", resp)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+		})
+	}
+}
+
+func TestCafeCancelOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockCoffeeshopUsecase(ctrl)
+	handler := NewCafeGrpcHandler(mockUC)
+
+	ctx := t.Context()
+
+	tests := []struct {
+		name         string
+		req          *cafepb.CancelOrderRequest
+		mockExpect   func()
+		expectedCode codes.Code
+		expectedRes  *cafepb.CancelOrderResponse
+	}{
+		{
+			name: "Success",
+			req:  &cafepb.CancelOrderRequest{TicketId: "T1"},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					CancelOrder(ctx, "T1").
+					Return(entity.Ticket{
+						ID:     "T1",
+						Orders: []entity.OrderProgress{{Order: entity.Order{ID: 1, Drink: entity.DrinkLatte}, Lifecycle: entity.NewLifecycle(100), StepsTotal: 3}},
+					}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes: &cafepb.CancelOrderResponse{
+				Ticket: &cafepb.Ticket{
+					Id: "T1",
+					Orders: []*cafepb.OrderProgress{
+						{
+							OrderId:      1,
+							DrinkName:    "latte",
+							State:        cafepb.OrderState_ORDER_STATE_RECEIVED,
+							CurrentSteps: []*cafepb.Step{},
+							StepsTotal:   3,
+							Transitions:  []*cafepb.Transition{{To: cafepb.OrderState_ORDER_STATE_RECEIVED, AtMs: 100}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Error - Unknown Ticket",
+			req:  &cafepb.CancelOrderRequest{TicketId: "T2"},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					CancelOrder(ctx, "T2").
+					Return(entity.Ticket{}, apperrors.ErrNoTicket)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockExpect()
+
+			resp, err := handler.CancelOrder(ctx, tt.req)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.True(t, proto.Equal(tt.expectedRes, resp), "got %v", resp)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+		})
+	}
+}
+
+func TestCafePickUpOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockCoffeeshopUsecase(ctrl)
+	handler := NewCafeGrpcHandler(mockUC)
+
+	ctx := t.Context()
+
+	result := entity.OrderResult{OrderID: 1, Drink: entity.DrinkEspresso, Status: entity.OrderStatusCompleted}
+
+	tests := []struct {
+		name         string
+		req          *cafepb.PickUpOrderRequest
+		mockExpect   func()
+		expectedCode codes.Code
+		expectedRes  *cafepb.PickUpOrderResponse
+	}{
+		{
+			name: "Success",
+			req:  &cafepb.PickUpOrderRequest{TicketId: "T1"},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					PickUpOrder(ctx, "T1").
+					Return(entity.Ticket{
+						ID: "T1",
+						Orders: []entity.OrderProgress{{
+							Order: entity.Order{ID: 1, Drink: entity.DrinkEspresso},
+							Lifecycle: entity.Lifecycle{Transitions: []entity.Transition{
+								{To: entity.OrderStateReceived},
+								{From: entity.OrderStateReceived, To: entity.OrderStateQueued},
+								{From: entity.OrderStateQueued, To: entity.OrderStateBrewing},
+								{From: entity.OrderStateBrewing, To: entity.OrderStateReady, AtMs: 20},
+								{From: entity.OrderStateReady, To: entity.OrderStatePickedUp, AtMs: 30},
+							}},
+							StepsDone:  2,
+							StepsTotal: 2,
+							Result:     result,
+						}},
+					}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes: &cafepb.PickUpOrderResponse{
+				Ticket: &cafepb.Ticket{
+					Id: "T1",
+					Orders: []*cafepb.OrderProgress{{
+						OrderId:      1,
+						DrinkName:    "espresso",
+						State:        cafepb.OrderState_ORDER_STATE_PICKED_UP,
+						CurrentSteps: []*cafepb.Step{},
+						StepsDone:    2,
+						StepsTotal:   2,
+						Result:       toCafePbResult(result),
+						Transitions: []*cafepb.Transition{
+							{To: cafepb.OrderState_ORDER_STATE_RECEIVED},
+							{From: cafepb.OrderState_ORDER_STATE_RECEIVED, To: cafepb.OrderState_ORDER_STATE_QUEUED},
+							{From: cafepb.OrderState_ORDER_STATE_QUEUED, To: cafepb.OrderState_ORDER_STATE_BREWING},
+							{From: cafepb.OrderState_ORDER_STATE_BREWING, To: cafepb.OrderState_ORDER_STATE_READY, AtMs: 20},
+							{From: cafepb.OrderState_ORDER_STATE_READY, To: cafepb.OrderState_ORDER_STATE_PICKED_UP, AtMs: 30},
+						},
+					}},
+					Done: true,
+				},
+			},
+		},
+		{
+			name: "Error - Unknown Ticket",
+			req:  &cafepb.PickUpOrderRequest{TicketId: "T2"},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					PickUpOrder(ctx, "T2").
+					Return(entity.Ticket{}, apperrors.ErrNoTicket)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "Error - Missing Ticket ID",
+			req:          &cafepb.PickUpOrderRequest{},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockExpect()
+
+			resp, err := handler.PickUpOrder(ctx, tt.req)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.True(t, proto.Equal(tt.expectedRes, resp), "got %v", resp)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package coffeeshop
 
 import (
 	"context"
+	"fmt"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	entity "gopher-cafe/internal/entity/coffeeshop"
@@ -14,10 +17,24 @@ import (
 )
 
 type CoffeeshopUsecase interface {
-	ExecuteBrew(ctx context.Context, orders []entity.Order, baristas int) []entity.OrderResult
+	ExecuteBrew(ctx context.Context, orders []entity.Order, baristas int, strategy entity.SchedulingStrategy) ([]entity.OrderResult, error)
+	StreamBrew(ctx context.Context, orders []entity.Order, baristas int, strategy entity.SchedulingStrategy, onEvent func(entity.BrewEvent)) ([]entity.OrderResult, error)
 	GetStats() (int64, int64, int64)
+	SubmitOrders(ctx context.Context, orders []entity.Order, baristas int, strategy entity.SchedulingStrategy) (entity.Ticket, error)
+	GetOrderStatus(ticketID string) (entity.Ticket, error)
+	CancelOrder(ctx context.Context, ticketID string) (entity.Ticket, error)
+	PickUpOrder(ctx context.Context, ticketID string) (entity.Ticket, error)
 }
 
+// SchedulerMetadataKey lets a client pick the scheduling strategy of a single
+// request, e.g. "x-scheduler: spt" or "x-scheduler: edf".
+const SchedulerMetadataKey = "x-scheduler"
+
+// OrderFailuresMetadataKey is the trailer of a GopherCafeService.ExecuteBrew
+// listing the orders that did not complete, one "<order id> <status>:
+// <reason>" value each, as its results have no room for them.
+const OrderFailuresMetadataKey = "x-order-failures"
+
 // Handler implements the gophercafepb.GopherCafeServiceServer interface
 type CoffeeshopGrpcHandler struct {
 	pb.UnimplementedGopherCafeServiceServer
@@ -33,33 +50,28 @@ func NewCoffeeshopGrpcHandler(uc CoffeeshopUsecase) *CoffeeshopGrpcHandler {
 // ExecuteBrew (CRP-01) triggers the simulation
 func (h *CoffeeshopGrpcHandler) ExecuteBrew(ctx context.Context, req *pb.ExecuteBrewRequest) (*pb.ExecuteBrewResponse, error) {
 	logger.Infof("Incoming request: %+v", req)
-	// 1. CRP-01: Validation
-	if !(req.Baristas >= 1) {
-		return nil, status.Error(codes.InvalidArgument, "at least 1 barista is required")
-	}
-	if len(req.Orders) == 0 {
-		return nil, status.Error(codes.InvalidArgument, "at least 1 order is required")
-	}
-
-	// 2. Mapping: Protobuf -> Domain Entities (CRP-08)
-	internalOrders := make([]entity.Order, len(req.Orders))
-	for i, o := range req.Orders {
-		if o.Id <= 0 {
-			return nil, status.Errorf(codes.InvalidArgument, "invalid order id at index %d", i)
-		}
-
-		internalOrders[i] = entity.Order{
-			ID:    o.Id,
-			Drink: toEntityDrink(o.Drink),
-		}
+	// 1. CRP-01: Validation and Mapping: Protobuf -> Domain Entities (CRP-08)
+	internalOrders, strategy, err := brewInput(ctx, req.Baristas, req.Orders)
+	if err != nil {
+		return nil, err
 	}
 
 	// 3. Execution: Call the Usecase
-	results := h.uc.ExecuteBrew(ctx, internalOrders, int(req.Baristas))
+	results, err := h.uc.ExecuteBrew(ctx, internalOrders, int(req.Baristas), strategy)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
 
 	// 4. Mapping: Domain Entities -> Protobuf Response (CRP-05)
+	// every order is returned, the ones that did not complete have no steps
+	// and are told apart in the trailer
+	var failures []string
 	protoResults := make([]*pb.Result, len(results))
 	for i, res := range results {
+		if res.Status != entity.OrderStatusCompleted {
+			failures = append(failures, fmt.Sprintf("%d %s: %s", res.OrderID, res.Status, res.Error))
+		}
+
 		protoSteps := make([]*pb.Step, len(res.Steps))
 		for j, step := range res.Steps {
 			protoSteps[j] = &pb.Step{
@@ -75,6 +87,12 @@ func (h *CoffeeshopGrpcHandler) ExecuteBrew(ctx context.Context, req *pb.Execute
 		}
 	}
 
+	if len(failures) > 0 {
+		if err := grpc.SetTrailer(ctx, metadata.MD{OrderFailuresMetadataKey: failures}); err != nil {
+			logger.Errorf("Sending the order failures failed: %s", err)
+		}
+	}
+
 	return &pb.ExecuteBrewResponse{
 		Results: protoResults,
 	}, nil
@@ -89,3 +107,50 @@ func (h *CoffeeshopGrpcHandler) GetStats(ctx context.Context, req *pb.GetStatsRe
 		P90ProcessingMilliseconds: p90RequestsMs,
 	}, nil
 }
+
+// brewInput checks a brew request and maps its orders, returning them with
+// the scheduling strategy the client asked for.
+func brewInput[T protoOrder](ctx context.Context, baristas int32, orders []T) ([]entity.Order, entity.SchedulingStrategy, error) {
+	if err := validateBrew(baristas, len(orders)); err != nil {
+		return nil, "", err
+	}
+
+	strategy, err := schedulingFromMetadata(ctx)
+	if err != nil {
+		return nil, "", status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	internalOrders := toEntityOrders(orders)
+	if err := entity.ValidateOrders(internalOrders); err != nil {
+		return nil, "", toStatusError(err)
+	}
+
+	return internalOrders, strategy, nil
+}
+
+func validateBrew(baristas int32, orders int) error {
+	if !(baristas >= 1) {
+		return status.Error(codes.InvalidArgument, "at least 1 barista is required")
+	}
+	if orders == 0 {
+		return status.Error(codes.InvalidArgument, "at least 1 order is required")
+	}
+
+	return nil
+}
+
+// schedulingFromMetadata returns the strategy requested by the client, or an
+// empty one to let the usecase use its default.
+func schedulingFromMetadata(ctx context.Context) (entity.SchedulingStrategy, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", nil
+	}
+
+	values := md.Get(SchedulerMetadataKey)
+	if len(values) == 0 {
+		return "", nil
+	}
+
+	return entity.ParseSchedulingStrategy(values[0])
+}
@@ -1,12 +1,13 @@
 package coffeeshop
 
 import (
-	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	entity "gopher-cafe/internal/entity/coffeeshop"
@@ -23,9 +24,12 @@ func TestExecuteBrew(t *testing.T) {
 	mockUC := NewMockCoffeeshopUsecase(ctrl)
 	handler := NewCoffeeshopGrpcHandler(mockUC)
 
+	ctx := t.Context()
+
 	// Define test cases
 	tests := []struct {
 		name         string
+		md           metadata.MD
 		req          *pb.ExecuteBrewRequest
 		mockExpect   func()
 		expectedCode codes.Code
@@ -42,19 +46,47 @@ func TestExecuteBrew(t *testing.T) {
 			mockExpect: func() {
 				// We expect the usecase to be called exactly once
 				mockUC.EXPECT().
-					ExecuteBrew(t.Context(), gomock.Len(1), 1).
+					ExecuteBrew(ctx, gomock.Len(1), 1, entity.SchedulingStrategy("")).
 					Return([]entity.OrderResult{
 						{
 							OrderID: 101,
+							Status:  entity.OrderStatusCompleted,
 							Steps: []entity.StepExecution{
 								{Equipment: entity.EquipGrinder, StartTimeMs: 10, EndTimeMs: 15},
 							},
 						},
-					})
+					}, nil)
+			},
+			expectedCode: codes.OK,
+			expectedRes:  true,
+		},
+		{
+			name: "Success - Scheduler From Metadata",
+			md:   metadata.Pairs(SchedulerMetadataKey, "spt"),
+			req: &pb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders: []*pb.Order{
+					{Id: 101, Drink: pb.DrinkType_DRINK_TYPE_LATTE},
+				},
+			},
+			mockExpect: func() {
+				mockUC.EXPECT().
+					ExecuteBrew(gomock.Any(), gomock.Len(1), 1, entity.SchedulingShortestFirst).
+					Return([]entity.OrderResult{{OrderID: 101, Status: entity.OrderStatusCompleted}}, nil)
 			},
 			expectedCode: codes.OK,
 			expectedRes:  true,
 		},
+		{
+			name: "Error - Unknown Scheduler",
+			md:   metadata.Pairs(SchedulerMetadataKey, "random"),
+			req: &pb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders:   []*pb.Order{{Id: 1, Drink: pb.DrinkType_DRINK_TYPE_ESPRESSO}},
+			},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
 		{
 			name: "Error - Invalid Baristas (CRP-01)",
 			req: &pb.ExecuteBrewRequest{
@@ -82,6 +114,31 @@ func TestExecuteBrew(t *testing.T) {
 			mockExpect:   func() {},
 			expectedCode: codes.InvalidArgument,
 		},
+		{
+			name: "Error - Unknown Drink",
+			req: &pb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders: []*pb.Order{
+					{Id: 1, Drink: pb.DrinkType_DRINK_TYPE_ESPRESSO},
+					{Id: 2, Drink: pb.DrinkType_DRINK_TYPE_UNSPECIFIED},
+					{Id: 3, Drink: pb.DrinkType(99)},
+				},
+			},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Error - Duplicate Order ID",
+			req: &pb.ExecuteBrewRequest{
+				Baristas: 1,
+				Orders: []*pb.Order{
+					{Id: 1, Drink: pb.DrinkType_DRINK_TYPE_ESPRESSO},
+					{Id: 1, Drink: pb.DrinkType_DRINK_TYPE_LATTE},
+				},
+			},
+			mockExpect:   func() {},
+			expectedCode: codes.InvalidArgument,
+		},
 	}
 
 	for _, tt := range tests {
@@ -89,8 +146,13 @@ func TestExecuteBrew(t *testing.T) {
 			// Setup expectations for this specific test case
 			tt.mockExpect()
 
+			reqCtx := ctx
+			if tt.md != nil {
+				reqCtx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
 			// Execute the call
-			resp, err := handler.ExecuteBrew(context.Background(), tt.req)
+			resp, err := handler.ExecuteBrew(reqCtx, tt.req)
 
 			// Assertions
 			if tt.expectedCode == codes.OK {
@@ -107,3 +169,70 @@ func TestExecuteBrew(t *testing.T) {
 		})
 	}
 }
+
+// fakeTransportStream keeps the trailer an RPC sends.
+type fakeTransportStream struct {
+	grpc.ServerTransportStream
+	trailer metadata.MD
+}
+
+func (s *fakeTransportStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestExecuteBrewFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUC := NewMockCoffeeshopUsecase(ctrl)
+	handler := NewCoffeeshopGrpcHandler(mockUC)
+
+	tests := []struct {
+		name        string
+		results     []entity.OrderResult
+		wantTrailer []string
+	}{
+		{
+			name: "All Completed",
+			results: []entity.OrderResult{
+				{OrderID: 1, Status: entity.OrderStatusCompleted, Steps: []entity.StepExecution{{Equipment: entity.EquipGrinder, EndTimeMs: 5}}},
+			},
+		},
+		{
+			name: "Failed And Cancelled Orders",
+			results: []entity.OrderResult{
+				{OrderID: 1, Status: entity.OrderStatusCompleted, Steps: []entity.StepExecution{{Equipment: entity.EquipGrinder, EndTimeMs: 5}}},
+				{OrderID: 2, Status: entity.OrderStatusFailed, Error: "out of stock: oat_milk"},
+				{OrderID: 3, Status: entity.OrderStatusCancelled, Error: "context canceled"},
+			},
+			wantTrailer: []string{"2 Failed: out of stock: oat_milk", "3 Cancelled: context canceled"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := &fakeTransportStream{}
+			ctx := grpc.NewContextWithServerTransportStream(t.Context(), ts)
+
+			req := &pb.ExecuteBrewRequest{Baristas: 1}
+			for _, res := range tt.results {
+				req.Orders = append(req.Orders, &pb.Order{Id: res.OrderID, Drink: pb.DrinkType_DRINK_TYPE_LATTE})
+			}
+			mockUC.EXPECT().
+				ExecuteBrew(ctx, gomock.Len(len(tt.results)), 1, entity.SchedulingStrategy("")).
+				Return(tt.results, nil)
+
+			resp, err := handler.ExecuteBrew(ctx, req)
+
+			assert.NoError(t, err)
+			// every order sent is returned, the failed ones without steps
+			assert.Len(t, resp.Results, len(tt.results))
+			for i, res := range tt.results {
+				assert.Equal(t, res.OrderID, resp.Results[i].OrderId)
+				assert.Len(t, resp.Results[i].Steps, len(res.Steps))
+			}
+			assert.Equal(t, tt.wantTrailer, ts.trailer.Get(OrderFailuresMetadataKey))
+		})
+	}
+}
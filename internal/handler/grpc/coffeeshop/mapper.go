@@ -1,24 +1,167 @@
 package coffeeshop
 
 import (
+	"errors"
+	"strconv"
+	"strings"
+
 	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cafepb "gopher-cafe/pkg/gen/go/cafe/v1"
 
 	pb "github.com/rexyajaib/gopher-cafe/pkg/gen/go/v1"
 )
 
+// protoOrder is implemented by the orders of both the gopher-cafe and the cafe
+// services.
+type protoOrder interface {
+	GetId() int64
+	GetDrink() pb.DrinkType
+}
+
+// drinkNamer is implemented by the orders of the cafe service, which may name
+// a drink that has no protobuf enum value.
+type drinkNamer interface {
+	GetDrinkName() string
+}
+
+// modifiable is implemented by the orders of the cafe service.
+type modifiable interface {
+	GetModifiers() *cafepb.Modifiers
+}
+
+// dueOrder is implemented by the orders of the cafe service, which may have a
+// due time.
+type dueOrder interface {
+	GetDueAtMs() int64
+}
+
+// prioritized is implemented by the orders of the cafe service, which may be
+// of a higher class than a walk-in.
+type prioritized interface {
+	GetPriority() cafepb.Priority
+}
+
+// toEntityOrders maps the orders as they are, leaving it to
+// entity.ValidateOrders to reject the invalid ones.
+func toEntityOrders[T protoOrder](orders []T) []entity.Order {
+	internalOrders := make([]entity.Order, len(orders))
+	for i, o := range orders {
+		drink := toEntityDrink(o.GetDrink())
+		if named, ok := any(o).(drinkNamer); ok && named.GetDrinkName() != "" {
+			drink = entity.DrinkType(named.GetDrinkName())
+		}
+
+		var mods entity.Modifiers
+		if m, ok := any(o).(modifiable); ok {
+			mods = toEntityModifiers(m.GetModifiers())
+		}
+
+		var dueAtMs int64
+		if due, ok := any(o).(dueOrder); ok {
+			dueAtMs = due.GetDueAtMs()
+		}
+
+		var priority entity.Priority
+		if p, ok := any(o).(prioritized); ok {
+			priority = entity.Priority(enumName(cafepb.Priority_name, int32(p.GetPriority()), "PRIORITY_"))
+		}
+
+		internalOrders[i] = entity.Order{
+			ID:        o.GetId(),
+			Drink:     drink,
+			Modifiers: mods,
+			DueAtMs:   dueAtMs,
+			Priority:  priority,
+		}
+	}
+
+	return internalOrders
+}
+
+// toEntityModifiers maps the enum values unknown to this server to modifiers
+// that entity.ValidateOrders rejects.
+func toEntityModifiers(m *cafepb.Modifiers) entity.Modifiers {
+	return entity.Modifiers{
+		Size:       entity.Size(enumName(cafepb.Size_name, int32(m.GetSize()), "SIZE_")),
+		ExtraShots: int(m.GetExtraShots()),
+		Milk:       entity.Milk(enumName(cafepb.Milk_name, int32(m.GetMilk()), "MILK_")),
+		Iced:       m.GetIced(),
+	}
+}
+
+// enumName returns the lower case name of an enum value without its prefix,
+// e.g. "large" for SIZE_LARGE, and "" for the unspecified value.
+func enumName(names map[int32]string, value int32, prefix string) string {
+	if value == 0 {
+		return ""
+	}
+
+	name, ok := names[value]
+	if !ok {
+		return strconv.Itoa(int(value))
+	}
+
+	return strings.ToLower(strings.TrimPrefix(name, prefix))
+}
+
+// toStatusError maps the usecase errors to the gRPC status returned to the
+// client.
+func toStatusError(err error) error {
+	var verr *apperrors.ValidationError
+	if errors.As(err, &verr) {
+		return status.Error(codes.InvalidArgument, verr.Error())
+	}
+	if errors.Is(err, apperrors.ErrNoTicket) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// drinkTypePrefix is shared by the protobuf names of the drinks, which are
+// otherwise the upper case names of the menu, e.g. DRINK_TYPE_LATTE for
+// "latte".
+const drinkTypePrefix = "DRINK_TYPE_"
+
 func toEntityDrink(d pb.DrinkType) entity.DrinkType {
-	switch d {
-	case pb.DrinkType_DRINK_TYPE_ESPRESSO:
-		return entity.DrinkEspresso
-	case pb.DrinkType_DRINK_TYPE_LATTE:
-		return entity.DrinkLatte
-	case pb.DrinkType_DRINK_TYPE_FRAPPE:
-		return entity.DrinkFrappe
-	case pb.DrinkType_DRINK_TYPE_MATCHA:
-		return entity.DrinkMatcha
-	default:
+	name, ok := pb.DrinkType_name[int32(d)]
+	if !ok || d == pb.DrinkType_DRINK_TYPE_UNSPECIFIED {
 		return entity.DrinkUnspecified
 	}
+
+	return entity.DrinkType(strings.ToLower(strings.TrimPrefix(name, drinkTypePrefix)))
+}
+
+// toPbDrink returns DRINK_TYPE_UNSPECIFIED for the drinks of the menu that have
+// no protobuf enum value.
+func toPbDrink(d entity.DrinkType) pb.DrinkType {
+	if d == entity.DrinkUnspecified {
+		return pb.DrinkType_DRINK_TYPE_UNSPECIFIED
+	}
+
+	return pb.DrinkType(pb.DrinkType_value[drinkTypePrefix+strings.ToUpper(string(d))])
+}
+
+func toEntityEquipment(e pb.EquipmentType) (entity.EquipmentType, bool) {
+	switch e {
+	case pb.EquipmentType_EQUIPMENT_TYPE_GRINDER:
+		return entity.EquipGrinder, true
+	case pb.EquipmentType_EQUIPMENT_TYPE_ESPRESSO_MACHINE:
+		return entity.EquipEspressoMachine, true
+	case pb.EquipmentType_EQUIPMENT_TYPE_MILK_STEAMER:
+		return entity.EquipMilkSteamer, true
+	case pb.EquipmentType_EQUIPMENT_TYPE_BLENDER:
+		return entity.EquipBlender, true
+	case pb.EquipmentType_EQUIPMENT_TYPE_WHISK:
+		return entity.EquipWhisk, true
+	default:
+		return 0, false
+	}
 }
 
 func toPbEquipment(e entity.EquipmentType) pb.EquipmentType {
@@ -37,3 +180,183 @@ func toPbEquipment(e entity.EquipmentType) pb.EquipmentType {
 		return pb.EquipmentType_EQUIPMENT_TYPE_UNSPECIFIED
 	}
 }
+
+func toCafePbStep(step entity.StepExecution) *cafepb.Step {
+	return &cafepb.Step{
+		Equipment:    toPbEquipment(step.Equipment),
+		StartMs:      step.StartTimeMs,
+		EndMs:        step.EndTimeMs,
+		QueuedAtMs:   step.QueuedAtMs,
+		AcquiredAtMs: step.AcquiredAtMs,
+		ReleasedAtMs: step.ReleasedAtMs,
+		WorkerId:     int32(step.WorkerID),
+	}
+}
+
+func toCafePbResult(res entity.OrderResult) *cafepb.Result {
+	steps := make([]*cafepb.Step, len(res.Steps))
+	for i, step := range res.Steps {
+		steps[i] = toCafePbStep(step)
+	}
+
+	return &cafepb.Result{
+		OrderId:     res.OrderID,
+		Drink:       toPbDrink(res.Drink),
+		DrinkName:   string(res.Drink),
+		Steps:       steps,
+		Status:      toCafePbOrderStatus(res.Status),
+		Error:       res.Error,
+		DueAtMs:     res.DueAtMs,
+		LatenessMs:  res.LatenessMs,
+		MetDeadline: res.MetDeadline(),
+		Priority:    toCafePbPriority(res.Priority),
+	}
+}
+
+func toCafePbPriority(p entity.Priority) cafepb.Priority {
+	if p == entity.PriorityWalkIn {
+		return cafepb.Priority_PRIORITY_UNSPECIFIED
+	}
+
+	return cafepb.Priority(cafepb.Priority_value["PRIORITY_"+strings.ToUpper(string(p))])
+}
+
+func toCafePbOrderStatus(s entity.OrderStatus) cafepb.OrderStatus {
+	switch s {
+	case entity.OrderStatusCompleted:
+		return cafepb.OrderStatus_ORDER_STATUS_COMPLETED
+	case entity.OrderStatusFailed:
+		return cafepb.OrderStatus_ORDER_STATUS_FAILED
+	case entity.OrderStatusCancelled:
+		return cafepb.OrderStatus_ORDER_STATUS_CANCELLED
+	default:
+		return cafepb.OrderStatus_ORDER_STATUS_UNSPECIFIED
+	}
+}
+
+func toCafePbEvent(ev entity.BrewEvent) *cafepb.BrewEvent {
+	event := &cafepb.BrewEvent{
+		OrderId: ev.OrderID,
+		TimeMs:  ev.TimeMs,
+	}
+
+	switch ev.Type {
+	case entity.BrewEventStepStarted:
+		event.Type = cafepb.BrewEventType_BREW_EVENT_TYPE_STEP_STARTED
+		event.StepIndex = int32(ev.StepIndex)
+		event.Step = toCafePbStep(ev.Step)
+	case entity.BrewEventStepFinished:
+		event.Type = cafepb.BrewEventType_BREW_EVENT_TYPE_STEP_FINISHED
+		event.StepIndex = int32(ev.StepIndex)
+		event.Step = toCafePbStep(ev.Step)
+	case entity.BrewEventOrderCompleted:
+		event.Type = cafepb.BrewEventType_BREW_EVENT_TYPE_ORDER_COMPLETED
+		event.Result = toCafePbResult(ev.Result)
+	case entity.BrewEventOrderFailed:
+		event.Type = cafepb.BrewEventType_BREW_EVENT_TYPE_ORDER_FAILED
+		event.Result = toCafePbResult(ev.Result)
+	case entity.BrewEventOrderState:
+		event.Type = cafepb.BrewEventType_BREW_EVENT_TYPE_ORDER_STATE
+		event.Transition = toCafePbTransition(ev.Transition)
+	}
+
+	return event
+}
+
+func toCafePbIngredientLevel(level entity.IngredientLevel) *cafepb.IngredientLevel {
+	return &cafepb.IngredientLevel{
+		Ingredient: string(level.Ingredient),
+		Quantity:   level.Quantity,
+		LowStock:   level.LowStock,
+	}
+}
+
+func toCafePbEquipmentStats(equipType entity.EquipmentType, stats entity.EquipmentStats) *cafepb.EquipmentStats {
+	busy := make([]int64, len(stats.WorkerBusyTime))
+	for i, d := range stats.WorkerBusyTime {
+		busy[i] = d.Milliseconds()
+	}
+
+	return &cafepb.EquipmentStats{
+		Equipment:       toPbEquipment(equipType),
+		Workers:         uint32(stats.Workers),
+		BusyWorkers:     uint32(stats.BusyWorkers),
+		QueueDepth:      uint32(stats.QueueDepth),
+		JobsCompleted:   stats.JobsCompleted,
+		WaitMs:          stats.WaitTime.Milliseconds(),
+		ProcessingMs:    stats.ProcessingTime.Milliseconds(),
+		WorkerBusyMs:    busy,
+		Utilization:     stats.Utilization,
+		BrokenWorkers:   uint32(stats.Broken),
+		CleaningWorkers: uint32(stats.Cleaning),
+		Breakdowns:      stats.Breakdowns,
+		Cleanings:       stats.Cleanings,
+		RepairMs:        stats.RepairTime.Milliseconds(),
+		CleaningMs:      stats.CleaningTime.Milliseconds(),
+	}
+}
+
+func toCafePbTicket(t entity.Ticket) *cafepb.Ticket {
+	orders := make([]*cafepb.OrderProgress, len(t.Orders))
+	for i, o := range t.Orders {
+		steps := make([]*cafepb.Step, len(o.CurrentSteps))
+		for j, step := range o.CurrentSteps {
+			steps[j] = toCafePbStep(step)
+		}
+
+		transitions := make([]*cafepb.Transition, len(o.Lifecycle.Transitions))
+		for j, tr := range o.Lifecycle.Transitions {
+			transitions[j] = toCafePbTransition(tr)
+		}
+
+		orders[i] = &cafepb.OrderProgress{
+			OrderId:      o.Order.ID,
+			DrinkName:    string(o.Order.Drink),
+			State:        toCafePbOrderState(o.Lifecycle.State()),
+			CurrentSteps: steps,
+			StepsDone:    int32(o.StepsDone),
+			StepsTotal:   int32(o.StepsTotal),
+			Transitions:  transitions,
+		}
+		if o.Lifecycle.State().Brewed() {
+			orders[i].Result = toCafePbResult(o.Result)
+		}
+	}
+
+	return &cafepb.Ticket{
+		Id:          t.ID,
+		CreatedAtMs: t.CreatedAtMs,
+		Orders:      orders,
+		Done:        t.Done(),
+	}
+}
+
+func toCafePbTransition(tr entity.Transition) *cafepb.Transition {
+	return &cafepb.Transition{
+		From: toCafePbOrderState(tr.From),
+		To:   toCafePbOrderState(tr.To),
+		Step: int32(tr.Step),
+		AtMs: tr.AtMs,
+	}
+}
+
+func toCafePbOrderState(s entity.OrderState) cafepb.OrderState {
+	switch s {
+	case entity.OrderStateReceived:
+		return cafepb.OrderState_ORDER_STATE_RECEIVED
+	case entity.OrderStateQueued:
+		return cafepb.OrderState_ORDER_STATE_QUEUED
+	case entity.OrderStateBrewing:
+		return cafepb.OrderState_ORDER_STATE_BREWING
+	case entity.OrderStateReady:
+		return cafepb.OrderState_ORDER_STATE_READY
+	case entity.OrderStatePickedUp:
+		return cafepb.OrderState_ORDER_STATE_PICKED_UP
+	case entity.OrderStateCancelled:
+		return cafepb.OrderState_ORDER_STATE_CANCELLED
+	case entity.OrderStateFailed:
+		return cafepb.OrderState_ORDER_STATE_FAILED
+	default:
+		return cafepb.OrderState_ORDER_STATE_UNSPECIFIED
+	}
+}
@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: admin.go
+//
+// Generated by this command:
+//
+//	mockgen -source=admin.go -destination=mock_admin_test.go -package=coffeeshop
+//
+
+// Package coffeeshop is a generated GoMock package.
+package coffeeshop
+
+import (
+	coffeeshop "gopher-cafe/internal/entity/coffeeshop"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAdminUsecase is a mock of AdminUsecase interface.
+type MockAdminUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockAdminUsecaseMockRecorder is the mock recorder for MockAdminUsecase.
+type MockAdminUsecaseMockRecorder struct {
+	mock *MockAdminUsecase
+}
+
+// NewMockAdminUsecase creates a new mock instance.
+func NewMockAdminUsecase(ctrl *gomock.Controller) *MockAdminUsecase {
+	mock := &MockAdminUsecase{ctrl: ctrl}
+	mock.recorder = &MockAdminUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminUsecase) EXPECT() *MockAdminUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetEquipmentStats mocks base method.
+func (m *MockAdminUsecase) GetEquipmentStats() map[coffeeshop.EquipmentType]coffeeshop.EquipmentStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEquipmentStats")
+	ret0, _ := ret[0].(map[coffeeshop.EquipmentType]coffeeshop.EquipmentStats)
+	return ret0
+}
+
+// GetEquipmentStats indicates an expected call of GetEquipmentStats.
+func (mr *MockAdminUsecaseMockRecorder) GetEquipmentStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEquipmentStats", reflect.TypeOf((*MockAdminUsecase)(nil).GetEquipmentStats))
+}
+
+// GetInventory mocks base method.
+func (m *MockAdminUsecase) GetInventory() []coffeeshop.IngredientLevel {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInventory")
+	ret0, _ := ret[0].([]coffeeshop.IngredientLevel)
+	return ret0
+}
+
+// GetInventory indicates an expected call of GetInventory.
+func (mr *MockAdminUsecaseMockRecorder) GetInventory() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventory", reflect.TypeOf((*MockAdminUsecase)(nil).GetInventory))
+}
+
+// ResizeEquipment mocks base method.
+func (m *MockAdminUsecase) ResizeEquipment(equipType coffeeshop.EquipmentType, workers uint8) (uint8, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResizeEquipment", equipType, workers)
+	ret0, _ := ret[0].(uint8)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResizeEquipment indicates an expected call of ResizeEquipment.
+func (mr *MockAdminUsecaseMockRecorder) ResizeEquipment(equipType, workers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResizeEquipment", reflect.TypeOf((*MockAdminUsecase)(nil).ResizeEquipment), equipType, workers)
+}
+
+// Restock mocks base method.
+func (m *MockAdminUsecase) Restock(ingredient coffeeshop.Ingredient, quantity int64) (coffeeshop.IngredientLevel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restock", ingredient, quantity)
+	ret0, _ := ret[0].(coffeeshop.IngredientLevel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restock indicates an expected call of Restock.
+func (mr *MockAdminUsecaseMockRecorder) Restock(ingredient, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restock", reflect.TypeOf((*MockAdminUsecase)(nil).Restock), ingredient, quantity)
+}
@@ -41,18 +41,49 @@ func (m *MockCoffeeshopUsecase) EXPECT() *MockCoffeeshopUsecaseMockRecorder {
 	return m.recorder
 }
 
+// CancelOrder mocks base method.
+func (m *MockCoffeeshopUsecase) CancelOrder(ctx context.Context, ticketID string) (coffeeshop.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, ticketID)
+	ret0, _ := ret[0].(coffeeshop.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockCoffeeshopUsecaseMockRecorder) CancelOrder(ctx, ticketID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockCoffeeshopUsecase)(nil).CancelOrder), ctx, ticketID)
+}
+
 // ExecuteBrew mocks base method.
-func (m *MockCoffeeshopUsecase) ExecuteBrew(ctx context.Context, orders []coffeeshop.Order, baristas int) []coffeeshop.OrderResult {
+func (m *MockCoffeeshopUsecase) ExecuteBrew(ctx context.Context, orders []coffeeshop.Order, baristas int, strategy coffeeshop.SchedulingStrategy) ([]coffeeshop.OrderResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExecuteBrew", ctx, orders, baristas)
+	ret := m.ctrl.Call(m, "ExecuteBrew", ctx, orders, baristas, strategy)
 	ret0, _ := ret[0].([]coffeeshop.OrderResult)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ExecuteBrew indicates an expected call of ExecuteBrew.
-func (mr *MockCoffeeshopUsecaseMockRecorder) ExecuteBrew(ctx, orders, baristas any) *gomock.Call {
+func (mr *MockCoffeeshopUsecaseMockRecorder) ExecuteBrew(ctx, orders, baristas, strategy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteBrew", reflect.TypeOf((*MockCoffeeshopUsecase)(nil).ExecuteBrew), ctx, orders, baristas, strategy)
+}
+
+// GetOrderStatus mocks base method.
+func (m *MockCoffeeshopUsecase) GetOrderStatus(ticketID string) (coffeeshop.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderStatus", ticketID)
+	ret0, _ := ret[0].(coffeeshop.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderStatus indicates an expected call of GetOrderStatus.
+func (mr *MockCoffeeshopUsecaseMockRecorder) GetOrderStatus(ticketID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteBrew", reflect.TypeOf((*MockCoffeeshopUsecase)(nil).ExecuteBrew), ctx, orders, baristas)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderStatus", reflect.TypeOf((*MockCoffeeshopUsecase)(nil).GetOrderStatus), ticketID)
 }
 
 // GetStats mocks base method.
@@ -70,3 +101,48 @@ func (mr *MockCoffeeshopUsecaseMockRecorder) GetStats() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockCoffeeshopUsecase)(nil).GetStats))
 }
+
+// PickUpOrder mocks base method.
+func (m *MockCoffeeshopUsecase) PickUpOrder(ctx context.Context, ticketID string) (coffeeshop.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PickUpOrder", ctx, ticketID)
+	ret0, _ := ret[0].(coffeeshop.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PickUpOrder indicates an expected call of PickUpOrder.
+func (mr *MockCoffeeshopUsecaseMockRecorder) PickUpOrder(ctx, ticketID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickUpOrder", reflect.TypeOf((*MockCoffeeshopUsecase)(nil).PickUpOrder), ctx, ticketID)
+}
+
+// StreamBrew mocks base method.
+func (m *MockCoffeeshopUsecase) StreamBrew(ctx context.Context, orders []coffeeshop.Order, baristas int, strategy coffeeshop.SchedulingStrategy, onEvent func(coffeeshop.BrewEvent)) ([]coffeeshop.OrderResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamBrew", ctx, orders, baristas, strategy, onEvent)
+	ret0, _ := ret[0].([]coffeeshop.OrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamBrew indicates an expected call of StreamBrew.
+func (mr *MockCoffeeshopUsecaseMockRecorder) StreamBrew(ctx, orders, baristas, strategy, onEvent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamBrew", reflect.TypeOf((*MockCoffeeshopUsecase)(nil).StreamBrew), ctx, orders, baristas, strategy, onEvent)
+}
+
+// SubmitOrders mocks base method.
+func (m *MockCoffeeshopUsecase) SubmitOrders(ctx context.Context, orders []coffeeshop.Order, baristas int, strategy coffeeshop.SchedulingStrategy) (coffeeshop.Ticket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitOrders", ctx, orders, baristas, strategy)
+	ret0, _ := ret[0].(coffeeshop.Ticket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitOrders indicates an expected call of SubmitOrders.
+func (mr *MockCoffeeshopUsecaseMockRecorder) SubmitOrders(ctx, orders, baristas, strategy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitOrders", reflect.TypeOf((*MockCoffeeshopUsecase)(nil).SubmitOrders), ctx, orders, baristas, strategy)
+}
@@ -0,0 +1,127 @@
+package inventory
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+
+	"github.com/ajaibid/coin-common-golang/logger"
+)
+
+// Inventory keeps the quantity in stock of every ingredient.
+type Inventory struct {
+	mu     sync.Mutex
+	levels map[entity.Ingredient]*entity.IngredientLevel
+}
+
+func New() *Inventory {
+	return &Inventory{
+		levels: make(map[entity.Ingredient]*entity.IngredientLevel),
+	}
+}
+
+// Stock adds the ingredients not in stock yet with their quantity, and sets
+// the low stock threshold of every given ingredient. The quantity of the
+// ingredients already in stock is left alone, it only changes by restocking
+// and consuming them.
+func (inv *Inventory) Stock(levels []entity.IngredientLevel) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	for _, l := range levels {
+		if level, ok := inv.levels[l.Ingredient]; ok {
+			level.LowStock = l.LowStock
+			continue
+		}
+
+		level := l
+		inv.levels[l.Ingredient] = &level
+		logger.Infof("Stocked %d %s", l.Quantity, l.Ingredient)
+	}
+}
+
+// Reserve takes the quantities needed by an order out of stock, either all of
+// them or, when one of them is short, none. The error of a shortage wraps
+// ErrOutOfStock and tells every ingredient that is short.
+func (inv *Inventory) Reserve(needs map[entity.Ingredient]int64) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	ingredients := slices.Sorted(maps.Keys(needs))
+
+	var short []string
+	for _, ingredient := range ingredients {
+		var left int64
+		if level, ok := inv.levels[ingredient]; ok {
+			left = level.Quantity
+		}
+		if left < needs[ingredient] {
+			short = append(short, fmt.Sprintf("%s (needs %d, %d left)", ingredient, needs[ingredient], left))
+		}
+	}
+	if len(short) > 0 {
+		return fmt.Errorf("%w: %s", apperrors.ErrOutOfStock, strings.Join(short, ", "))
+	}
+
+	for _, ingredient := range ingredients {
+		level := inv.levels[ingredient]
+		before := level.Quantity
+		level.Quantity -= needs[ingredient]
+
+		// logged once, when the threshold is crossed
+		if before >= level.LowStock && level.Quantity < level.LowStock {
+			logger.Infof("Low stock of %s: %d left, below %d", ingredient, level.Quantity, level.LowStock)
+		}
+	}
+
+	return nil
+}
+
+// Release puts the quantities reserved by an order that did not complete back
+// in stock.
+func (inv *Inventory) Release(needs map[entity.Ingredient]int64) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	for ingredient, quantity := range needs {
+		if level, ok := inv.levels[ingredient]; ok {
+			level.Quantity += quantity
+		}
+	}
+}
+
+// Restock adds quantity to the stock of an ingredient and returns its new
+// level. Only the ingredients stocked before can be restocked, so that a typo
+// does not go unnoticed.
+func (inv *Inventory) Restock(ingredient entity.Ingredient, quantity int64) (entity.IngredientLevel, error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	level, ok := inv.levels[ingredient]
+	if !ok {
+		return entity.IngredientLevel{}, fmt.Errorf("%w: %s", apperrors.ErrNoIngredient, ingredient)
+	}
+
+	level.Quantity += quantity
+	logger.Infof("Restocked %d %s, %d in stock", quantity, ingredient, level.Quantity)
+
+	return *level, nil
+}
+
+// Levels returns the level of every ingredient in stock by name.
+func (inv *Inventory) Levels() []entity.IngredientLevel {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	levels := make([]entity.IngredientLevel, 0, len(inv.levels))
+	for _, ingredient := range slices.Sorted(maps.Keys(inv.levels)) {
+		levels = append(levels, *inv.levels[ingredient])
+	}
+
+	return levels
+}
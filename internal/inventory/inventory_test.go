@@ -0,0 +1,87 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+)
+
+func TestReserve(t *testing.T) {
+	tests := []struct {
+		name       string
+		needs      map[entity.Ingredient]int64
+		wantErr    error
+		wantLevels map[entity.Ingredient]int64
+	}{
+		{
+			name:       "in stock",
+			needs:      map[entity.Ingredient]int64{entity.IngredientBeans: 18, entity.IngredientMilk: 200},
+			wantLevels: map[entity.Ingredient]int64{entity.IngredientBeans: 82, entity.IngredientMilk: 50},
+		},
+		{
+			name:       "nothing needed",
+			wantLevels: map[entity.Ingredient]int64{entity.IngredientBeans: 100, entity.IngredientMilk: 250},
+		},
+		{
+			name:       "one short takes none",
+			needs:      map[entity.Ingredient]int64{entity.IngredientBeans: 18, entity.IngredientMilk: 300},
+			wantErr:    apperrors.ErrOutOfStock,
+			wantLevels: map[entity.Ingredient]int64{entity.IngredientBeans: 100, entity.IngredientMilk: 250},
+		},
+		{
+			name:       "never stocked",
+			needs:      map[entity.Ingredient]int64{entity.IngredientIce: 1},
+			wantErr:    apperrors.ErrOutOfStock,
+			wantLevels: map[entity.Ingredient]int64{entity.IngredientBeans: 100, entity.IngredientMilk: 250},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inv := New()
+			inv.Stock([]entity.IngredientLevel{
+				{Ingredient: entity.IngredientBeans, Quantity: 100, LowStock: 50},
+				{Ingredient: entity.IngredientMilk, Quantity: 250},
+			})
+
+			err := inv.Reserve(test.needs)
+			assert.ErrorIs(t, err, test.wantErr)
+
+			levels := make(map[entity.Ingredient]int64)
+			for _, level := range inv.Levels() {
+				levels[level.Ingredient] = level.Quantity
+			}
+			assert.Equal(t, test.wantLevels, levels)
+
+			if err == nil {
+				inv.Release(test.needs)
+				assert.Equal(t, int64(100), inv.Levels()[0].Quantity)
+			}
+		})
+	}
+}
+
+func TestStock(t *testing.T) {
+	inv := New()
+	inv.Stock([]entity.IngredientLevel{{Ingredient: entity.IngredientMilk, Quantity: 250, LowStock: 50}})
+	assert.NoError(t, inv.Reserve(map[entity.Ingredient]int64{entity.IngredientMilk: 200}))
+
+	// stocking again only sets the threshold of what is in stock already
+	inv.Stock([]entity.IngredientLevel{
+		{Ingredient: entity.IngredientMilk, Quantity: 250, LowStock: 100},
+		{Ingredient: entity.IngredientIce, Quantity: 40},
+	})
+	assert.Equal(t, []entity.IngredientLevel{
+		{Ingredient: entity.IngredientIce, Quantity: 40},
+		{Ingredient: entity.IngredientMilk, Quantity: 50, LowStock: 100},
+	}, inv.Levels())
+
+	level, err := inv.Restock(entity.IngredientMilk, 500)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.IngredientLevel{Ingredient: entity.IngredientMilk, Quantity: 550, LowStock: 100}, level)
+
+	_, err = inv.Restock(entity.IngredientMatcha, 10)
+	assert.ErrorIs(t, err, apperrors.ErrNoIngredient)
+}
@@ -0,0 +1,146 @@
+package menu
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+)
+
+// Expand turns an order into the steps to brew and the ingredients to consume,
+// applying its modifiers to the recipe of its drink:
+//   - iced drops the milk steamer steps, the steps after them waiting on what
+//     they waited on instead, and adds ice unless the drink has some already
+//   - every extra shot pulls one more espresso, after the last espresso of the
+//     recipe, and consumes the beans of the drink once more
+//   - another milk is consumed in place of the milk of the drink
+//   - the size scales the step durations and the ingredient quantities
+//
+// Modifiers that do not apply to the drink, e.g. an extra shot of matcha, or
+// that need an ingredient the menu does not stock, e.g. oat milk, return an
+// error wrapping ErrBadModifiers.
+func (m *Menu) Expand(order entity.Order) ([]entity.RecipeStep, map[entity.Ingredient]int64, error) {
+	recipe, ok := m.recipes[order.Drink]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", apperrors.ErrUnknownRecipe, order.Drink)
+	}
+
+	mods := order.Modifiers
+	steps := slices.Clone(recipe)
+	ingredients := maps.Clone(m.ingredients[order.Drink])
+	if ingredients == nil {
+		ingredients = make(map[entity.Ingredient]int64)
+	}
+
+	if mods.Milk != entity.MilkRegular {
+		milk, ok := ingredients[entity.IngredientMilk]
+		steams := slices.ContainsFunc(steps, func(step entity.RecipeStep) bool {
+			return step.Equipment == entity.EquipMilkSteamer
+		})
+		if !ok && !steams {
+			return nil, nil, fmt.Errorf("%w: %s has no milk to replace", apperrors.ErrBadModifiers, order.Drink)
+		}
+		if ok {
+			if !m.stocks(mods.Milk.Ingredient()) {
+				return nil, nil, fmt.Errorf("%w: %s is not stocked", apperrors.ErrBadModifiers, mods.Milk.Ingredient())
+			}
+			delete(ingredients, entity.IngredientMilk)
+			ingredients[mods.Milk.Ingredient()] = milk
+		}
+	}
+
+	if mods.ExtraShots > 0 {
+		last := -1
+		for i, step := range steps {
+			if step.Equipment == entity.EquipEspressoMachine {
+				last = i
+			}
+		}
+		if last < 0 {
+			return nil, nil, fmt.Errorf("%w: %s has no espresso shot", apperrors.ErrBadModifiers, order.Drink)
+		}
+
+		shot := steps[last]
+		for range mods.ExtraShots {
+			steps = append(steps, entity.RecipeStep{
+				Equipment: shot.Equipment,
+				Duration:  shot.Duration,
+				DependsOn: []int{last},
+			})
+			last = len(steps) - 1
+		}
+		if beans, ok := ingredients[entity.IngredientBeans]; ok {
+			ingredients[entity.IngredientBeans] = beans * int64(1+mods.ExtraShots)
+		}
+	}
+
+	if mods.Iced {
+		steps = without(steps, entity.EquipMilkSteamer)
+		if len(steps) == 0 {
+			return nil, nil, fmt.Errorf("%w: %s has nothing left to brew when iced", apperrors.ErrBadModifiers, order.Drink)
+		}
+		if _, ok := ingredients[entity.IngredientIce]; !ok {
+			if !m.stocks(entity.IngredientIce) {
+				return nil, nil, fmt.Errorf("%w: %s is not stocked", apperrors.ErrBadModifiers, entity.IngredientIce)
+			}
+			ingredients[entity.IngredientIce] = entity.IcedQuantity
+		}
+	}
+
+	if scale := mods.Size.Scale(); scale != 1 {
+		for i := range steps {
+			steps[i].Duration = time.Duration(float64(steps[i].Duration) * scale)
+		}
+		for ingredient, quantity := range ingredients {
+			ingredients[ingredient] = int64(math.Ceil(float64(quantity) * scale))
+		}
+	}
+
+	return steps, ingredients, nil
+}
+
+// stocks tells whether the inventory of the menu has the ingredient.
+func (m *Menu) stocks(ingredient entity.Ingredient) bool {
+	return slices.ContainsFunc(m.inventory, func(level entity.IngredientLevel) bool {
+		return level.Ingredient == ingredient
+	})
+}
+
+// without drops the steps of an equipment from a recipe. The steps that
+// depended on a dropped step depend on its own dependencies instead, so the
+// recipe keeps its order.
+func without(recipe []entity.RecipeStep, equip entity.EquipmentType) []entity.RecipeStep {
+	steps := make([]entity.RecipeStep, 0, len(recipe))
+	index := make([]int, len(recipe)) // new index of a kept step
+	deps := make([][]int, len(recipe))
+
+	for i, step := range recipe {
+		for _, dep := range step.DependsOn {
+			if index[dep] >= 0 {
+				deps[i] = append(deps[i], index[dep])
+			} else {
+				deps[i] = append(deps[i], deps[dep]...)
+			}
+		}
+		slices.Sort(deps[i])
+		deps[i] = slices.Compact(deps[i])
+
+		if step.Equipment == equip {
+			index[i] = -1
+			continue
+		}
+
+		index[i] = len(steps)
+		steps = append(steps, entity.RecipeStep{
+			Equipment: step.Equipment,
+			Duration:  step.Duration,
+			DependsOn: deps[i],
+		})
+	}
+
+	return steps
+}
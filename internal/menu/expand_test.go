@@ -0,0 +1,186 @@
+package menu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+)
+
+func TestExpand(t *testing.T) {
+	m, err := Parse([]byte(`
+inventory:
+  beans: {stock: 1000}
+  milk: {stock: 1000}
+  oat_milk: {stock: 1000}
+  ice: {stock: 1000}
+  matcha_powder: {stock: 1000}
+drinks:
+  - name: espresso
+    ingredients: {beans: 18}
+    steps:
+      - equipment: grinder
+        duration: 4ms
+      - equipment: espresso_machine
+        duration: 8ms
+  - name: latte
+    ingredients: {beans: 18, milk: 200}
+    steps:
+      - equipment: grinder
+        duration: 4ms
+      - equipment: espresso_machine
+        duration: 8ms
+      - equipment: milk_steamer
+        duration: 16ms
+        after: []
+  - name: frappe
+    ingredients: {beans: 18, milk: 150, ice: 50}
+    steps:
+      - equipment: grinder
+        duration: 4ms
+      - equipment: blender
+        duration: 12ms
+  - name: matcha
+    ingredients: {matcha_powder: 4, milk: 200}
+    steps:
+      - equipment: grinder
+        duration: 4ms
+      - equipment: milk_steamer
+        duration: 16ms
+        after: []
+      - equipment: whisk
+        duration: 4ms
+        after: [grinder, milk_steamer]
+`))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		order           entity.Order
+		wantSteps       []entity.RecipeStep
+		wantIngredients map[entity.Ingredient]int64
+		wantErr         error
+	}{
+		{
+			name:  "as on the menu",
+			order: entity.Order{Drink: entity.DrinkEspresso},
+			wantSteps: []entity.RecipeStep{
+				{Equipment: entity.EquipGrinder, Duration: 4 * time.Millisecond},
+				{Equipment: entity.EquipEspressoMachine, Duration: 8 * time.Millisecond, DependsOn: []int{0}},
+			},
+			wantIngredients: map[entity.Ingredient]int64{entity.IngredientBeans: 18},
+		},
+		{
+			name:  "large",
+			order: entity.Order{Drink: entity.DrinkEspresso, Modifiers: entity.Modifiers{Size: entity.SizeLarge}},
+			wantSteps: []entity.RecipeStep{
+				{Equipment: entity.EquipGrinder, Duration: 5 * time.Millisecond},
+				{Equipment: entity.EquipEspressoMachine, Duration: 10 * time.Millisecond, DependsOn: []int{0}},
+			},
+			wantIngredients: map[entity.Ingredient]int64{entity.IngredientBeans: 23},
+		},
+		{
+			name:  "two extra shots",
+			order: entity.Order{Drink: entity.DrinkEspresso, Modifiers: entity.Modifiers{ExtraShots: 2}},
+			wantSteps: []entity.RecipeStep{
+				{Equipment: entity.EquipGrinder, Duration: 4 * time.Millisecond},
+				{Equipment: entity.EquipEspressoMachine, Duration: 8 * time.Millisecond, DependsOn: []int{0}},
+				{Equipment: entity.EquipEspressoMachine, Duration: 8 * time.Millisecond, DependsOn: []int{1}},
+				{Equipment: entity.EquipEspressoMachine, Duration: 8 * time.Millisecond, DependsOn: []int{2}},
+			},
+			wantIngredients: map[entity.Ingredient]int64{entity.IngredientBeans: 54},
+		},
+		{
+			name:  "oat milk",
+			order: entity.Order{Drink: entity.DrinkLatte, Modifiers: entity.Modifiers{Milk: entity.MilkOat}},
+			wantSteps: []entity.RecipeStep{
+				{Equipment: entity.EquipGrinder, Duration: 4 * time.Millisecond},
+				{Equipment: entity.EquipEspressoMachine, Duration: 8 * time.Millisecond, DependsOn: []int{0}},
+				{Equipment: entity.EquipMilkSteamer, Duration: 16 * time.Millisecond},
+			},
+			wantIngredients: map[entity.Ingredient]int64{entity.IngredientBeans: 18, "oat_milk": 200},
+		},
+		{
+			name:  "iced",
+			order: entity.Order{Drink: entity.DrinkLatte, Modifiers: entity.Modifiers{Iced: true}},
+			wantSteps: []entity.RecipeStep{
+				{Equipment: entity.EquipGrinder, Duration: 4 * time.Millisecond},
+				{Equipment: entity.EquipEspressoMachine, Duration: 8 * time.Millisecond, DependsOn: []int{0}},
+			},
+			wantIngredients: map[entity.Ingredient]int64{entity.IngredientBeans: 18, entity.IngredientMilk: 200, entity.IngredientIce: entity.IcedQuantity},
+		},
+		{
+			name:  "iced keeps the steps after the steamer in order",
+			order: entity.Order{Drink: entity.DrinkMatcha, Modifiers: entity.Modifiers{Iced: true, Size: entity.SizeSmall}},
+			wantSteps: []entity.RecipeStep{
+				{Equipment: entity.EquipGrinder, Duration: 3 * time.Millisecond},
+				{Equipment: entity.EquipWhisk, Duration: 3 * time.Millisecond, DependsOn: []int{0}},
+			},
+			wantIngredients: map[entity.Ingredient]int64{entity.IngredientMatcha: 3, entity.IngredientMilk: 150, entity.IngredientIce: 75},
+		},
+		{
+			name:  "iced drink has its own ice",
+			order: entity.Order{Drink: entity.DrinkFrappe, Modifiers: entity.Modifiers{Iced: true}},
+			wantSteps: []entity.RecipeStep{
+				{Equipment: entity.EquipGrinder, Duration: 4 * time.Millisecond},
+				{Equipment: entity.EquipBlender, Duration: 12 * time.Millisecond, DependsOn: []int{0}},
+			},
+			wantIngredients: map[entity.Ingredient]int64{entity.IngredientBeans: 18, entity.IngredientMilk: 150, entity.IngredientIce: 50},
+		},
+		{
+			name:    "extra shot without espresso",
+			order:   entity.Order{Drink: entity.DrinkMatcha, Modifiers: entity.Modifiers{ExtraShots: 1}},
+			wantErr: apperrors.ErrBadModifiers,
+		},
+		{
+			name:    "milk without milk",
+			order:   entity.Order{Drink: entity.DrinkEspresso, Modifiers: entity.Modifiers{Milk: entity.MilkSoy}},
+			wantErr: apperrors.ErrBadModifiers,
+		},
+		{
+			name:    "milk not stocked",
+			order:   entity.Order{Drink: entity.DrinkLatte, Modifiers: entity.Modifiers{Milk: entity.MilkAlmond}},
+			wantErr: apperrors.ErrBadModifiers,
+		},
+		{
+			name:    "not on the menu",
+			order:   entity.Order{Drink: "tea"},
+			wantErr: apperrors.ErrUnknownRecipe,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			steps, ingredients, err := m.Expand(test.order)
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantSteps, steps)
+			assert.Equal(t, test.wantIngredients, ingredients)
+		})
+	}
+
+	// a menu without ice takes no iced orders
+	noIce, err := Parse([]byte(`
+drinks:
+  - name: latte
+    steps:
+      - equipment: espresso_machine
+        duration: 8ms
+      - equipment: milk_steamer
+        duration: 16ms
+`))
+	assert.NoError(t, err)
+	_, _, err = noIce.Expand(entity.Order{Drink: entity.DrinkLatte, Modifiers: entity.Modifiers{Iced: true}})
+	assert.ErrorIs(t, err, apperrors.ErrBadModifiers)
+
+	// the menu is left as it was
+	latte, _ := m.Recipe(entity.DrinkLatte)
+	assert.Len(t, latte, 3)
+	assert.Equal(t, map[entity.Ingredient]int64{entity.IngredientBeans: 18, entity.IngredientMilk: 200}, m.Ingredients(entity.DrinkLatte))
+}
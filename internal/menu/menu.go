@@ -0,0 +1,331 @@
+package menu
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Menu holds the recipe and the ingredients of every drink that can be
+// ordered, the number of workers and the faults of the equipment it sets, and
+// the ingredients to stock.
+type Menu struct {
+	recipes     map[entity.DrinkType][]entity.RecipeStep
+	ingredients map[entity.DrinkType]map[entity.Ingredient]int64
+	workers     map[entity.EquipmentType]uint8
+	faults      map[entity.EquipmentType]entity.FaultModel
+	inventory   []entity.IngredientLevel
+}
+
+// drinkName keeps the drink names mappable to and from the protobuf enum
+// values, e.g. "iced_latte" and DRINK_TYPE_ICED_LATTE.
+var drinkName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// New builds a menu from the recipe of each drink. A step may only depend on
+// the steps listed before it, which keeps every recipe free of cycles.
+func New(recipes map[entity.DrinkType][]entity.RecipeStep) (*Menu, error) {
+	if len(recipes) == 0 {
+		return nil, errors.New("no drinks")
+	}
+
+	for drink, recipe := range recipes {
+		if !drinkName.MatchString(string(drink)) {
+			return nil, fmt.Errorf("drink %q: name must be lower snake case", drink)
+		}
+		if len(recipe) == 0 {
+			return nil, fmt.Errorf("drink %q: no steps", drink)
+		}
+
+		for i, step := range recipe {
+			if step.Duration <= 0 {
+				return nil, fmt.Errorf("drink %q step %d: duration must be positive", drink, i)
+			}
+			for _, dep := range step.DependsOn {
+				if dep < 0 || dep >= i {
+					return nil, fmt.Errorf("drink %q step %d: can only depend on an earlier step, got %d", drink, i, dep)
+				}
+			}
+		}
+	}
+
+	return &Menu{recipes: maps.Clone(recipes)}, nil
+}
+
+// Recipe returns the steps of the drink and whether it is on the menu.
+func (m *Menu) Recipe(drink entity.DrinkType) ([]entity.RecipeStep, bool) {
+	recipe, ok := m.recipes[drink]
+	return recipe, ok
+}
+
+// Ingredients returns the quantity of every ingredient a drink consumes.
+func (m *Menu) Ingredients(drink entity.DrinkType) map[entity.Ingredient]int64 {
+	return maps.Clone(m.ingredients[drink])
+}
+
+// Drinks returns the drinks on the menu by name.
+func (m *Menu) Drinks() []entity.DrinkType {
+	return slices.Sorted(maps.Keys(m.recipes))
+}
+
+// Workers returns the number of workers of the equipment set by the menu file,
+// if any. The equipment it leaves out keeps its workers.
+func (m *Menu) Workers() map[entity.EquipmentType]uint8 {
+	return maps.Clone(m.workers)
+}
+
+// Faults returns the fault model of the equipment set by the menu file, if
+// any. The equipment it leaves out keeps its fault model.
+func (m *Menu) Faults() map[entity.EquipmentType]entity.FaultModel {
+	return maps.Clone(m.faults)
+}
+
+// Inventory returns the ingredients to stock, with the quantity they are
+// first stocked with and their low stock threshold.
+func (m *Menu) Inventory() []entity.IngredientLevel {
+	return slices.Clone(m.inventory)
+}
+
+// Validate checks that every equipment used by the menu has workers.
+func (m *Menu) Validate(workers map[entity.EquipmentType]uint8) error {
+	var errs []error
+	for _, drink := range m.Drinks() {
+		for i, step := range m.recipes[drink] {
+			if workers[step.Equipment] == 0 {
+				errs = append(errs, fmt.Errorf("drink %q step %d: no %s available", drink, i, step.Equipment))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// file is the layout of a menu file. Steps run in the listed order, each one
+// starting once the one before it is done, unless it names the steps it waits
+// for in After. An empty After starts the step right away.
+type file struct {
+	Equipment map[string]equipment `yaml:"equipment"`
+	Inventory map[string]struct {
+		Stock    int64 `yaml:"stock"`
+		LowStock int64 `yaml:"low_stock"`
+	} `yaml:"inventory"`
+	Drinks []struct {
+		Name        string           `yaml:"name"`
+		Ingredients map[string]int64 `yaml:"ingredients"`
+		Steps       []struct {
+			ID        string        `yaml:"id"`
+			Equipment string        `yaml:"equipment"`
+			Duration  time.Duration `yaml:"duration"`
+			After     []string      `yaml:"after"`
+		} `yaml:"steps"`
+	} `yaml:"drinks"`
+}
+
+// equipment is an entry of the equipment section of a menu file. It is either
+// the number of workers of the equipment or a mapping that also sets its
+// faults, in which the number of workers is optional.
+type equipment struct {
+	Workers     *uint8        `yaml:"workers"`
+	MTBF        time.Duration `yaml:"mtbf"`
+	Repair      time.Duration `yaml:"repair"`
+	CleanEvery  int           `yaml:"clean_every"`
+	Cleaning    time.Duration `yaml:"cleaning"`
+	OnBreakdown string        `yaml:"on_breakdown"`
+
+	faulty bool // set as a mapping
+}
+
+func (e *equipment) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		e.Workers = new(uint8)
+		return node.Decode(e.Workers)
+	}
+
+	// the decoder of the file does not reject unknown fields below a custom
+	// unmarshaler, so they are checked here
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i < len(node.Content); i += 2 {
+			switch key := node.Content[i].Value; key {
+			case "workers", "mtbf", "repair", "clean_every", "cleaning", "on_breakdown":
+			default:
+				return fmt.Errorf("line %d: field %s not found in equipment", node.Content[i].Line, key)
+			}
+		}
+	}
+
+	type plain equipment
+	e.faulty = true
+	return node.Decode((*plain)(e))
+}
+
+func (e equipment) faults() (entity.FaultModel, error) {
+	faults := entity.FaultModel{
+		MTBF:         e.MTBF,
+		RepairTime:   e.Repair,
+		CleanEvery:   e.CleanEvery,
+		CleaningTime: e.Cleaning,
+		OnBreakdown:  entity.BreakdownPolicy(e.OnBreakdown),
+	}
+	if faults.OnBreakdown == "" {
+		faults.OnBreakdown = entity.BreakdownWait
+	}
+
+	switch {
+	case faults.MTBF < 0 || faults.CleanEvery < 0:
+		return faults, errors.New("mtbf and clean_every cannot be negative")
+	case faults.MTBF > 0 && faults.RepairTime <= 0:
+		return faults, errors.New("repair must be positive when mtbf is set")
+	case faults.CleanEvery > 0 && faults.CleaningTime <= 0:
+		return faults, errors.New("cleaning must be positive when clean_every is set")
+	case faults.OnBreakdown != entity.BreakdownWait && faults.OnBreakdown != entity.BreakdownFail:
+		return faults, fmt.Errorf("on_breakdown must be %s or %s, got %q", entity.BreakdownWait, entity.BreakdownFail, faults.OnBreakdown)
+	}
+
+	return faults, nil
+}
+
+// Load reads a menu file. The file is YAML, or JSON since JSON is valid YAML.
+func Load(path string) (*Menu, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read menu: %w", err)
+	}
+
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse menu %s: %w", path, err)
+	}
+
+	return m, nil
+}
+
+// Parse reads a menu from the content of a menu file. Steps are referred to in
+// After by their ID, which defaults to their equipment.
+func Parse(data []byte) (*Menu, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var f file
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	inventory := make([]entity.IngredientLevel, 0, len(f.Inventory))
+	for name, stock := range f.Inventory {
+		if stock.Stock < 0 || stock.LowStock < 0 {
+			return nil, fmt.Errorf("inventory %q: stock and low_stock cannot be negative", name)
+		}
+		inventory = append(inventory, entity.IngredientLevel{
+			Ingredient: entity.Ingredient(name),
+			Quantity:   stock.Stock,
+			LowStock:   stock.LowStock,
+		})
+	}
+	slices.SortFunc(inventory, func(a, b entity.IngredientLevel) int {
+		return strings.Compare(string(a.Ingredient), string(b.Ingredient))
+	})
+
+	recipes := make(map[entity.DrinkType][]entity.RecipeStep, len(f.Drinks))
+	ingredients := make(map[entity.DrinkType]map[entity.Ingredient]int64, len(f.Drinks))
+	for _, d := range f.Drinks {
+		drink := entity.DrinkType(d.Name)
+		if _, ok := recipes[drink]; ok {
+			return nil, fmt.Errorf("drink %q: listed twice", drink)
+		}
+
+		if len(d.Ingredients) > 0 {
+			ingredients[drink] = make(map[entity.Ingredient]int64, len(d.Ingredients))
+		}
+		for name, quantity := range d.Ingredients {
+			if _, ok := f.Inventory[name]; !ok {
+				return nil, fmt.Errorf("drink %q: ingredient %q is not in the inventory", drink, name)
+			}
+			if quantity <= 0 {
+				return nil, fmt.Errorf("drink %q: quantity of %q must be positive", drink, name)
+			}
+			ingredients[drink][entity.Ingredient(name)] = quantity
+		}
+
+		ids := make(map[string]int, len(d.Steps))
+		recipe := make([]entity.RecipeStep, len(d.Steps))
+		for i, s := range d.Steps {
+			equip, err := entity.ParseEquipmentType(s.Equipment)
+			if err != nil {
+				return nil, fmt.Errorf("drink %q step %d: %w", drink, i, err)
+			}
+
+			id := s.ID
+			if id == "" {
+				id = s.Equipment
+			}
+			if _, ok := ids[id]; ok {
+				return nil, fmt.Errorf("drink %q step %d: id %q used twice", drink, i, id)
+			}
+			ids[id] = i
+
+			var deps []int
+			switch {
+			case s.After == nil && i > 0:
+				deps = []int{i - 1}
+			case s.After != nil:
+				for _, after := range s.After {
+					dep, ok := ids[after]
+					if !ok {
+						return nil, fmt.Errorf("drink %q step %d: no earlier step %q", drink, i, after)
+					}
+					deps = append(deps, dep)
+				}
+			}
+
+			recipe[i] = entity.RecipeStep{
+				Equipment: equip,
+				Duration:  s.Duration,
+				DependsOn: deps,
+			}
+		}
+		recipes[drink] = recipe
+	}
+
+	workers := make(map[entity.EquipmentType]uint8, len(f.Equipment))
+	faults := make(map[entity.EquipmentType]entity.FaultModel)
+	for name, e := range f.Equipment {
+		equip, err := entity.ParseEquipmentType(name)
+		if err != nil {
+			return nil, fmt.Errorf("equipment: %w", err)
+		}
+
+		if e.Workers != nil {
+			if *e.Workers == 0 {
+				return nil, fmt.Errorf("equipment %q: at least 1 worker is required", name)
+			}
+			workers[equip] = *e.Workers
+		}
+
+		if e.faulty {
+			if faults[equip], err = e.faults(); err != nil {
+				return nil, fmt.Errorf("equipment %q: %w", name, err)
+			}
+		}
+	}
+
+	m, err := New(recipes)
+	if err != nil {
+		return nil, err
+	}
+	m.ingredients = ingredients
+	m.workers = workers
+	m.faults = faults
+	m.inventory = inventory
+
+	return m, nil
+}
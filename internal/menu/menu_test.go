@@ -0,0 +1,209 @@
+package menu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		want        map[entity.DrinkType][]entity.RecipeStep
+		wantWorkers map[entity.EquipmentType]uint8
+		wantFaults  map[entity.EquipmentType]entity.FaultModel
+		wantStock   []entity.IngredientLevel
+		wantErr     bool
+	}{
+		{
+			name: "yaml",
+			data: `
+equipment:
+  grinder: 2
+  milk_steamer:
+    mtbf: 1h
+    repair: 5m
+    on_breakdown: fail
+  whisk:
+    workers: 3
+    clean_every: 10
+    cleaning: 30s
+inventory:
+  milk: {stock: 1000, low_stock: 100}
+  matcha_powder: {stock: 50}
+drinks:
+  - name: matcha
+    ingredients: {matcha_powder: 4, milk: 200}
+    steps:
+      - equipment: grinder
+        duration: 5ms
+      - id: steam
+        equipment: milk_steamer
+        duration: 15ms
+        after: []
+      - equipment: whisk
+        duration: 3ms
+        after: [grinder, steam]
+`,
+			want: map[entity.DrinkType][]entity.RecipeStep{
+				entity.DrinkMatcha: {
+					{Equipment: entity.EquipGrinder, Duration: 5 * time.Millisecond},
+					{Equipment: entity.EquipMilkSteamer, Duration: 15 * time.Millisecond},
+					{Equipment: entity.EquipWhisk, Duration: 3 * time.Millisecond, DependsOn: []int{0, 1}},
+				},
+			},
+			wantWorkers: map[entity.EquipmentType]uint8{entity.EquipGrinder: 2, entity.EquipWhisk: 3},
+			wantFaults: map[entity.EquipmentType]entity.FaultModel{
+				entity.EquipMilkSteamer: {MTBF: time.Hour, RepairTime: 5 * time.Minute, OnBreakdown: entity.BreakdownFail},
+				entity.EquipWhisk:       {CleanEvery: 10, CleaningTime: 30 * time.Second, OnBreakdown: entity.BreakdownWait},
+			},
+			wantStock: []entity.IngredientLevel{
+				{Ingredient: entity.IngredientMatcha, Quantity: 50},
+				{Ingredient: entity.IngredientMilk, Quantity: 1000, LowStock: 100},
+			},
+		},
+		{
+			name: "json",
+			data: `{"drinks": [{"name": "espresso", "steps": [
+				{"equipment": "Grinder", "duration": "5ms"},
+				{"equipment": "EspressoMachine", "duration": "8ms"}
+			]}]}`,
+			want: map[entity.DrinkType][]entity.RecipeStep{
+				entity.DrinkEspresso: {
+					{Equipment: entity.EquipGrinder, Duration: 5 * time.Millisecond},
+					{Equipment: entity.EquipEspressoMachine, Duration: 8 * time.Millisecond, DependsOn: []int{0}},
+				},
+			},
+			wantWorkers: map[entity.EquipmentType]uint8{},
+			wantFaults:  map[entity.EquipmentType]entity.FaultModel{},
+			wantStock:   []entity.IngredientLevel{},
+		},
+		{
+			name:    "unknown equipment",
+			data:    "drinks: [{name: tea, steps: [{equipment: kettle, duration: 5ms}]}]",
+			wantErr: true,
+		},
+		{
+			name:    "equipment without workers",
+			data:    "equipment: {whisk: 0}",
+			wantErr: true,
+		},
+		{
+			name:    "mtbf without repair",
+			data:    "equipment: {whisk: {mtbf: 1h}}",
+			wantErr: true,
+		},
+		{
+			name:    "unknown breakdown policy",
+			data:    "equipment: {whisk: {mtbf: 1h, repair: 1m, on_breakdown: panic}}",
+			wantErr: true,
+		},
+		{
+			name:    "unknown equipment field",
+			data:    "equipment: {whisk: {workers: 1, colour: green}}",
+			wantErr: true,
+		},
+		{
+			name:    "ingredient not in the inventory",
+			data:    "drinks: [{name: tea, ingredients: {leaves: 3}, steps: [{equipment: whisk, duration: 5ms}]}]",
+			wantErr: true,
+		},
+		{
+			name:    "no quantity",
+			data:    "inventory: {leaves: {stock: 10}}\ndrinks: [{name: tea, ingredients: {leaves: 0}, steps: [{equipment: whisk, duration: 5ms}]}]",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			data:    "drinks: [{name: tea, steps: [{equipment: whisk, duration: 5ms, colour: green}]}]",
+			wantErr: true,
+		},
+		{
+			name:    "after a later step",
+			data:    "drinks: [{name: tea, steps: [{equipment: whisk, duration: 5ms, after: [grinder]}, {equipment: grinder, duration: 5ms}]}]",
+			wantErr: true,
+		},
+		{
+			name:    "listed twice",
+			data:    "drinks: [{name: tea, steps: [{equipment: whisk, duration: 5ms}]}, {name: tea, steps: [{equipment: whisk, duration: 5ms}]}]",
+			wantErr: true,
+		},
+		{
+			name:    "no duration",
+			data:    "drinks: [{name: tea, steps: [{equipment: whisk}]}]",
+			wantErr: true,
+		},
+		{
+			name:    "name not in snake case",
+			data:    "drinks: [{name: Green Tea, steps: [{equipment: whisk, duration: 5ms}]}]",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := Parse([]byte(test.data))
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, m.recipes)
+			assert.Equal(t, test.wantWorkers, m.Workers())
+			assert.Equal(t, test.wantFaults, m.Faults())
+			assert.Equal(t, test.wantStock, m.Inventory())
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	m, err := Load("../../config/menu.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.DrinkType{entity.DrinkEspresso, entity.DrinkFrappe, entity.DrinkLatte, entity.DrinkMatcha}, m.Drinks())
+
+	latte, ok := m.Recipe(entity.DrinkLatte)
+	assert.True(t, ok)
+	assert.Equal(t, 15*time.Millisecond, entity.RecipeDuration(latte))
+	assert.Equal(t, map[entity.Ingredient]int64{entity.IngredientBeans: 18, entity.IngredientMilk: 200}, m.Ingredients(entity.DrinkLatte))
+
+	assert.NoError(t, m.Validate(map[entity.EquipmentType]uint8{
+		entity.EquipGrinder:         1,
+		entity.EquipEspressoMachine: 1,
+		entity.EquipMilkSteamer:     1,
+		entity.EquipBlender:         1,
+		entity.EquipWhisk:           1,
+	}))
+	assert.Error(t, m.Validate(map[entity.EquipmentType]uint8{entity.EquipGrinder: 1}))
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "menu.yaml")
+	write := func(duration string) {
+		data := "drinks: [{name: espresso, steps: [{equipment: espresso_machine, duration: " + duration + "}]}]"
+		assert.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	}
+	write("8ms")
+
+	reloaded := make(chan *Menu, 10)
+	assert.NoError(t, Watch(t.Context(), path, func(m *Menu) {
+		reloaded <- m
+	}))
+
+	write("not a duration")
+	write("10ms")
+
+	select {
+	case m := <-reloaded:
+		recipe, ok := m.Recipe(entity.DrinkEspresso)
+		assert.True(t, ok)
+		assert.Equal(t, 10*time.Millisecond, recipe[0].Duration)
+	case <-time.After(time.Second):
+		t.Fatal("menu was not reloaded")
+	}
+}
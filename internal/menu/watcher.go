@@ -0,0 +1,74 @@
+package menu
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/ajaibid/coin-common-golang/logger"
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay lets the writes of a single save settle before the file is read,
+// so that a half written file is not loaded.
+const reloadDelay = 100 * time.Millisecond
+
+// Watch calls onChange with the new menu each time the file at path is saved,
+// until ctx is done. A file that fails to load is logged and skipped, so the
+// previous menu stays in use.
+//
+// The directory of the file is watched rather than the file itself, since
+// most editors save by replacing the file.
+func Watch(ctx context.Context, path string, onChange func(*Menu)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watch menu: %w", err)
+	}
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("watch menu: %w", err)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		reload := time.NewTimer(reloadDelay)
+		reload.Stop()
+		defer reload.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case ev, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(ev.Name) != filepath.Clean(path) || !ev.Has(fsnotify.Write|fsnotify.Create) {
+					continue
+				}
+				reload.Reset(reloadDelay)
+
+			case <-reload.C:
+				m, err := Load(path)
+				if err != nil {
+					logger.Errorf("Reloading menu failed, keeping the previous one: %s", err)
+					continue
+				}
+				logger.Infof("Menu reloaded from %s: %v", path, m.Drinks())
+				onChange(m)
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Errorf("Watching menu failed: %s", err)
+			}
+		}
+	}()
+
+	return nil
+}
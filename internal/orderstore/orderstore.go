@@ -0,0 +1,248 @@
+package orderstore
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"gopher-cafe/internal/clock"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+)
+
+// Store keeps the tickets of the orders brewed in the background in memory.
+// Tickets are forgotten once they are done for longer than the retention.
+type Store struct {
+	mu        sync.Mutex
+	tickets   map[string]*ticket
+	clock     clock.Clock
+	retention time.Duration
+}
+
+// ticket is the state of a ticket as brew events come in.
+type ticket struct {
+	entity.Ticket
+	index   map[int64]int                  // of the orders by ID
+	current []map[int]entity.StepExecution // by order, the started steps by recipe index
+	cancel  context.CancelFunc
+	doneAt  time.Time
+}
+
+func New(clk clock.Clock, retention time.Duration) *Store {
+	return &Store{
+		tickets:   make(map[string]*ticket),
+		clock:     clk,
+		retention: retention,
+	}
+}
+
+// Create adds a ticket for orders, all of them received. cancel stops brewing
+// the orders of the ticket, and is called once they are all brewed.
+func (s *Store) Create(orders []entity.OrderProgress, cancel context.CancelFunc) entity.Ticket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+
+	now := s.clock.Now().UnixMilli()
+	t := &ticket{
+		Ticket: entity.Ticket{
+			ID:          rand.Text(),
+			CreatedAtMs: now,
+			Orders:      slices.Clone(orders),
+		},
+		index:   make(map[int64]int, len(orders)),
+		current: make([]map[int]entity.StepExecution, len(orders)),
+		cancel:  cancel,
+	}
+	for i := range t.Orders {
+		t.Orders[i].Lifecycle = entity.NewLifecycle(now)
+		t.index[t.Orders[i].Order.ID] = i
+		t.current[i] = make(map[int]entity.StepExecution)
+	}
+	s.tickets[t.ID] = t
+
+	return t.snapshot()
+}
+
+// Apply updates the orders of a ticket with an event of their brew. The
+// orders move along their lifecycle with the state events, an illegal
+// transition being returned as an error.
+func (s *Store) Apply(ticketID string, ev entity.BrewEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tickets[ticketID]
+	if !ok {
+		return nil
+	}
+	i, ok := t.index[ev.OrderID]
+	if !ok {
+		return nil
+	}
+
+	order := &t.Orders[i]
+	if ev.Type == entity.BrewEventOrderState {
+		tr := ev.Transition
+		if _, err := order.Lifecycle.Transition(tr.To, tr.Step, tr.AtMs); err != nil {
+			return fmt.Errorf("order %d of ticket %s: %w", ev.OrderID, ticketID, err)
+		}
+		s.finish(t)
+		return nil
+	}
+	if order.Lifecycle.State().Brewed() {
+		return nil
+	}
+
+	switch ev.Type {
+	case entity.BrewEventStepStarted:
+		t.current[i][ev.StepIndex] = ev.Step
+	case entity.BrewEventStepFinished:
+		delete(t.current[i], ev.StepIndex)
+		order.StepsDone++
+	case entity.BrewEventOrderCompleted, entity.BrewEventOrderFailed:
+		order.Result = ev.Result
+		clear(t.current[i])
+	}
+
+	return nil
+}
+
+// Fail fails the orders of a ticket that are not brewed yet, when their brew
+// could not go on, and returns their transitions.
+func (s *Store) Fail(ticketID string, err error) []entity.Transition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tickets[ticketID]
+	if !ok {
+		return nil
+	}
+
+	var transitions []entity.Transition
+	now := s.clock.Now().UnixMilli()
+	for i := range t.Orders {
+		order := &t.Orders[i]
+		tr, terr := order.Lifecycle.Transition(entity.OrderStateFailed, 0, now)
+		if terr != nil {
+			continue
+		}
+		transitions = append(transitions, tr)
+		order.Result = entity.OrderResult{
+			OrderID:  order.Order.ID,
+			Drink:    order.Order.Drink,
+			Priority: order.Order.Priority,
+			Status:   entity.OrderStatusFailed,
+			Error:    err.Error(),
+		}
+		clear(t.current[i])
+	}
+
+	s.finish(t)
+
+	return transitions
+}
+
+// PickUp hands over the ready orders of a ticket and returns the ticket along
+// with the transitions of the orders picked up.
+func (s *Store) PickUp(ticketID string) (entity.Ticket, []entity.Transition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+
+	t, ok := s.tickets[ticketID]
+	if !ok {
+		return entity.Ticket{}, nil, fmt.Errorf("%w: %s", apperrors.ErrNoTicket, ticketID)
+	}
+
+	var transitions []entity.Transition
+	now := s.clock.Now().UnixMilli()
+	for i := range t.Orders {
+		order := &t.Orders[i]
+		if order.Lifecycle.State() != entity.OrderStateReady {
+			continue
+		}
+		tr, err := order.Lifecycle.Transition(entity.OrderStatePickedUp, 0, now)
+		if err != nil {
+			return entity.Ticket{}, nil, err
+		}
+		transitions = append(transitions, tr)
+	}
+
+	return t.snapshot(), transitions, nil
+}
+
+// Get returns the ticket of the given ID.
+func (s *Store) Get(ticketID string) (entity.Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+
+	t, ok := s.tickets[ticketID]
+	if !ok {
+		return entity.Ticket{}, fmt.Errorf("%w: %s", apperrors.ErrNoTicket, ticketID)
+	}
+
+	return t.snapshot(), nil
+}
+
+// Cancel stops brewing the orders of a ticket and returns the ticket. The
+// orders turn cancelled as the brew gives up on them, not right away, and the
+// orders that are brewed already are left alone.
+func (s *Store) Cancel(ticketID string) (entity.Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+
+	t, ok := s.tickets[ticketID]
+	if !ok {
+		return entity.Ticket{}, fmt.Errorf("%w: %s", apperrors.ErrNoTicket, ticketID)
+	}
+	t.cancel()
+
+	return t.snapshot(), nil
+}
+
+// finish releases the brew of a ticket once it is done. s.mu must be held.
+func (s *Store) finish(t *ticket) {
+	if !t.doneAt.IsZero() || !t.Done() {
+		return
+	}
+
+	t.doneAt = s.clock.Now()
+	t.cancel()
+}
+
+// sweep forgets the tickets done for longer than the retention. s.mu must be
+// held.
+func (s *Store) sweep() {
+	now := s.clock.Now()
+	for id, t := range s.tickets {
+		if !t.doneAt.IsZero() && now.Sub(t.doneAt) > s.retention {
+			delete(s.tickets, id)
+		}
+	}
+}
+
+func (t *ticket) snapshot() entity.Ticket {
+	snap := t.Ticket
+	snap.Orders = slices.Clone(t.Orders)
+	for i := range snap.Orders {
+		snap.Orders[i].Lifecycle = t.Orders[i].Lifecycle.Clone()
+		current := t.current[i]
+		snap.Orders[i].CurrentSteps = make([]entity.StepExecution, 0, len(current))
+		for _, index := range slices.Sorted(maps.Keys(current)) {
+			snap.Orders[i].CurrentSteps = append(snap.Orders[i].CurrentSteps, current[index])
+		}
+	}
+
+	return snap
+}
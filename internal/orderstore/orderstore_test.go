@@ -0,0 +1,217 @@
+package orderstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gopher-cafe/internal/clock"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+)
+
+func TestApply(t *testing.T) {
+	grinder := entity.StepExecution{Equipment: entity.EquipGrinder, StartTimeMs: 0}
+	steamer := entity.StepExecution{Equipment: entity.EquipMilkSteamer, StartTimeMs: 0}
+
+	state := func(from, to entity.OrderState, step int) entity.BrewEvent {
+		return entity.BrewEvent{
+			Type:       entity.BrewEventOrderState,
+			OrderID:    1,
+			Transition: entity.Transition{From: from, To: to, Step: step},
+		}
+	}
+	queued := state(entity.OrderStateReceived, entity.OrderStateQueued, 0)
+
+	tests := []struct {
+		name      string
+		events    []entity.BrewEvent
+		failWith  error
+		want      entity.OrderProgress
+		wantState entity.OrderState
+		wantErr   error
+		wantDone  bool
+		wantClose bool // whether the brew of the ticket was released
+	}{
+		{
+			name:      "received",
+			want:      entity.OrderProgress{CurrentSteps: []entity.StepExecution{}},
+			wantState: entity.OrderStateReceived,
+		},
+		{
+			name: "brewing two steps",
+			events: []entity.BrewEvent{
+				queued,
+				{Type: entity.BrewEventStepStarted, OrderID: 1, StepIndex: 2, Step: steamer},
+				state(entity.OrderStateQueued, entity.OrderStateBrewing, 2),
+				{Type: entity.BrewEventStepStarted, OrderID: 1, StepIndex: 0, Step: grinder},
+				state(entity.OrderStateBrewing, entity.OrderStateBrewing, 0),
+			},
+			want:      entity.OrderProgress{CurrentSteps: []entity.StepExecution{grinder, steamer}},
+			wantState: entity.OrderStateBrewing,
+		},
+		{
+			name: "brewing after a step",
+			events: []entity.BrewEvent{
+				queued,
+				{Type: entity.BrewEventStepStarted, OrderID: 1, StepIndex: 0, Step: grinder},
+				state(entity.OrderStateQueued, entity.OrderStateBrewing, 0),
+				{Type: entity.BrewEventStepStarted, OrderID: 1, StepIndex: 2, Step: steamer},
+				state(entity.OrderStateBrewing, entity.OrderStateBrewing, 2),
+				{Type: entity.BrewEventStepFinished, OrderID: 1, StepIndex: 0},
+			},
+			want:      entity.OrderProgress{CurrentSteps: []entity.StepExecution{steamer}, StepsDone: 1},
+			wantState: entity.OrderStateBrewing,
+		},
+		{
+			name: "ready",
+			events: []entity.BrewEvent{
+				queued,
+				state(entity.OrderStateQueued, entity.OrderStateBrewing, 0),
+				{Type: entity.BrewEventOrderCompleted, OrderID: 1, Result: entity.OrderResult{OrderID: 1, Status: entity.OrderStatusCompleted}},
+				state(entity.OrderStateBrewing, entity.OrderStateReady, 0),
+				// events of a brewed order are dropped
+				{Type: entity.BrewEventStepStarted, OrderID: 1, StepIndex: 0, Step: grinder},
+			},
+			want: entity.OrderProgress{
+				CurrentSteps: []entity.StepExecution{},
+				Result:       entity.OrderResult{OrderID: 1, Status: entity.OrderStatusCompleted},
+			},
+			wantState: entity.OrderStateReady,
+			wantDone:  true,
+			wantClose: true,
+		},
+		{
+			name: "cancelled",
+			events: []entity.BrewEvent{
+				queued,
+				{Type: entity.BrewEventStepStarted, OrderID: 1, StepIndex: 0, Step: grinder},
+				state(entity.OrderStateQueued, entity.OrderStateBrewing, 0),
+				{Type: entity.BrewEventOrderFailed, OrderID: 1, Result: entity.OrderResult{OrderID: 1, Status: entity.OrderStatusCancelled}},
+				state(entity.OrderStateBrewing, entity.OrderStateCancelled, 0),
+			},
+			want: entity.OrderProgress{
+				CurrentSteps: []entity.StepExecution{},
+				Result:       entity.OrderResult{OrderID: 1, Status: entity.OrderStatusCancelled},
+			},
+			wantState: entity.OrderStateCancelled,
+			wantDone:  true,
+			wantClose: true,
+		},
+		{
+			name: "illegal transition",
+			events: []entity.BrewEvent{
+				state(entity.OrderStateReceived, entity.OrderStateReady, 0),
+			},
+			want:      entity.OrderProgress{CurrentSteps: []entity.StepExecution{}},
+			wantState: entity.OrderStateReceived,
+			wantErr:   apperrors.ErrIllegalTransition,
+		},
+		{
+			name:     "brew failed",
+			events:   []entity.BrewEvent{queued},
+			failWith: errors.New("boom"),
+			want: entity.OrderProgress{
+				CurrentSteps: []entity.StepExecution{},
+				Result:       entity.OrderResult{OrderID: 1, Drink: entity.DrinkLatte, Status: entity.OrderStatusFailed, Error: "boom"},
+			},
+			wantState: entity.OrderStateFailed,
+			wantDone:  true,
+			wantClose: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := New(clock.NewVirtual(time.UnixMilli(0)), time.Minute)
+
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+
+			ticket := store.Create([]entity.OrderProgress{
+				{Order: entity.Order{ID: 1, Drink: entity.DrinkLatte}, StepsTotal: 3},
+			}, cancel)
+			var err error
+			for _, ev := range test.events {
+				err = errors.Join(err, store.Apply(ticket.ID, ev))
+			}
+			assert.ErrorIs(t, err, test.wantErr)
+			if test.failWith != nil {
+				assert.Len(t, store.Fail(ticket.ID, test.failWith), 1)
+			}
+
+			got, err := store.Get(ticket.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, ticket.ID, got.ID)
+			assert.Equal(t, test.wantDone, got.Done())
+			assert.Equal(t, test.wantState, got.Orders[0].Lifecycle.State())
+
+			test.want.Order = entity.Order{ID: 1, Drink: entity.DrinkLatte}
+			test.want.StepsTotal = 3
+			got.Orders[0].Lifecycle = entity.Lifecycle{}
+			assert.Equal(t, []entity.OrderProgress{test.want}, got.Orders)
+			assert.Equal(t, test.wantClose, ctx.Err() != nil)
+		})
+	}
+}
+
+func TestPickUp(t *testing.T) {
+	clk := clock.NewVirtual(time.UnixMilli(0))
+	store := New(clk, time.Minute)
+
+	ticket := store.Create([]entity.OrderProgress{
+		{Order: entity.Order{ID: 1, Drink: entity.DrinkEspresso}},
+		{Order: entity.Order{ID: 2, Drink: entity.DrinkEspresso}},
+	}, func() {})
+	for _, tr := range []entity.Transition{
+		{To: entity.OrderStateQueued},
+		{To: entity.OrderStateBrewing},
+		{To: entity.OrderStateReady, AtMs: 10},
+	} {
+		assert.NoError(t, store.Apply(ticket.ID, entity.BrewEvent{Type: entity.BrewEventOrderState, OrderID: 1, Transition: tr}))
+	}
+
+	clk.Set(time.UnixMilli(20))
+	got, transitions, err := store.PickUp(ticket.ID)
+	assert.NoError(t, err)
+	// only the ready order is picked up
+	assert.Equal(t, []entity.Transition{{From: entity.OrderStateReady, To: entity.OrderStatePickedUp, AtMs: 20}}, transitions)
+	assert.Equal(t, entity.OrderStatePickedUp, got.Orders[0].Lifecycle.State())
+	assert.Equal(t, entity.OrderStateReceived, got.Orders[1].Lifecycle.State())
+
+	_, _, err = store.PickUp("unknown")
+	assert.ErrorIs(t, err, apperrors.ErrNoTicket)
+}
+
+func TestCancel(t *testing.T) {
+	clk := clock.NewVirtual(time.UnixMilli(0))
+	store := New(clk, time.Minute)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	ticket := store.Create([]entity.OrderProgress{{Order: entity.Order{ID: 1, Drink: entity.DrinkEspresso}}}, cancel)
+
+	got, err := store.Cancel(ticket.ID)
+	assert.NoError(t, err)
+	assert.Error(t, ctx.Err())
+	// the order turns cancelled once the brew gives up on it
+	assert.Equal(t, entity.OrderStateReceived, got.Orders[0].Lifecycle.State())
+
+	_, err = store.Cancel("unknown")
+	assert.ErrorIs(t, err, apperrors.ErrNoTicket)
+
+	// the ticket is forgotten a retention after it is done
+	assert.NoError(t, store.Apply(ticket.ID, entity.BrewEvent{
+		Type:       entity.BrewEventOrderState,
+		OrderID:    1,
+		Transition: entity.Transition{To: entity.OrderStateCancelled},
+	}))
+	clk.Advance(2 * time.Minute)
+
+	_, err = store.Get(ticket.ID)
+	assert.ErrorIs(t, err, apperrors.ErrNoTicket)
+}
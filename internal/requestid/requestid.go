@@ -0,0 +1,47 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+)
+
+// MetadataKey is the gRPC metadata key the request ID travels in, both ways.
+const MetadataKey = "x-request-id"
+
+// maxLen bounds the IDs taken from clients.
+const maxLen = 64
+
+type ctxKey struct{}
+
+// New returns a random request ID.
+func New() string {
+	return rand.Text()
+}
+
+// Valid tells whether an ID given by a client can be used as is, so that it
+// can be logged safely: up to 64 letters, digits, '-', '_' or '.'.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewContext returns a copy of ctx that carries id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, or an empty one.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
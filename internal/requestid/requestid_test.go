@@ -0,0 +1,33 @@
+package requestid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "uuid", id: "3f0c5a8e-9b1d-4c7e-a2f4-6d8b0e1c9a7f", want: true},
+		{name: "generated", id: New(), want: true},
+		{name: "empty", id: ""},
+		{name: "too long", id: strings.Repeat("a", 65)},
+		{name: "format verb", id: "abc%s"},
+		{name: "line break", id: "abc\nmethod=forged"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, Valid(test.id))
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	assert.Empty(t, FromContext(t.Context()))
+	assert.Equal(t, "abc", FromContext(NewContext(t.Context(), "abc")))
+}
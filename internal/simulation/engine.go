@@ -0,0 +1,462 @@
+package simulation
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"gopher-cafe/internal/clock"
+	"gopher-cafe/internal/menu"
+	"math/rand/v2"
+	"time"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+)
+
+// Engine brews orders as a discrete-event simulation. Instead of sleeping
+// through every step it jumps a virtual clock from one event to the next, so a
+// run takes no wall time and its step timestamps are exact and reproducible.
+//
+// It follows the rules of the worker pools: baristas pick the orders up in the
+// given sequence, a step starts once the steps it depends on are done and
+// waits for a free worker of its equipment behind the steps of a higher
+// priority. Workers are cleaned and break down like the ones of the pools, and
+// the first failing step of an order gives its other steps up.
+type Engine struct {
+	workers map[entity.EquipmentType]uint8
+	faults  map[entity.EquipmentType]entity.FaultModel
+	menu    *menu.Menu
+}
+
+func NewEngine(workers map[entity.EquipmentType]uint8, faults map[entity.EquipmentType]entity.FaultModel, m *menu.Menu) *Engine {
+	return &Engine{
+		workers: workers,
+		faults:  faults,
+		menu:    m,
+	}
+}
+
+// Run brews the orders with the given number of baristas, starting at the
+// given virtual time. Results are returned in completion order. Orders that
+// cannot be brewed, because their recipe is unknown or needs an equipment
+// without workers, fail right away without taking up a barista.
+//
+// The time left before the deadline of ctx is given in virtual time from
+// start: once it is over, the orders being brewed are cancelled and the ones
+// not picked up yet are left behind, like the requests that time out on the
+// pools. Breakdowns are drawn from a generator seeded with start, so that a
+// run is reproducible.
+func (e *Engine) Run(ctx context.Context, start time.Time, orders []entity.Order, baristas int) []entity.OrderResult {
+	seed := uint64(start.UnixNano())
+	r := &run{
+		ctx:     ctx,
+		clock:   clock.NewVirtual(start),
+		rand:    rand.New(rand.NewPCG(seed, seed)),
+		menu:    e.menu,
+		pools:   make(map[entity.EquipmentType]*pool, len(e.workers)),
+		queue:   make([]entity.Order, 0, len(orders)),
+		results: make([]entity.OrderResult, 0, len(orders)),
+	}
+	for _, order := range orders {
+		if err := e.check(order); err != nil {
+			r.results = append(r.results, result(order, entity.OrderStatusFailed, err))
+			continue
+		}
+		r.queue = append(r.queue, order)
+	}
+	for equipType, workers := range e.workers {
+		p := &pool{
+			faults: e.faults[equipType],
+			size:   int(workers),
+			wear:   make([]wear, workers),
+		}
+		for id := range int(workers) {
+			p.idle = append(p.idle, id)
+		}
+		r.pools[equipType] = p
+	}
+
+	if ctx.Err() != nil {
+		r.expire()
+		return r.results
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		// scheduled first, so that the steps due at the deadline are late
+		r.schedule(start.Add(time.Until(deadline)), r.expire)
+	}
+
+	for range baristas {
+		r.schedule(start, r.nextOrder)
+	}
+
+	for r.events.Len() > 0 {
+		ev := heap.Pop(&r.events).(*event)
+		r.clock.Set(ev.at)
+		ev.fire()
+	}
+
+	return r.results
+}
+
+func (e *Engine) check(order entity.Order) error {
+	recipe, _, err := e.menu.Expand(order)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range recipe {
+		if e.workers[step.Equipment] == 0 {
+			return fmt.Errorf("%w: %s", apperrors.ErrNoWorkerPool, step.Equipment)
+		}
+	}
+
+	return nil
+}
+
+// result reports an order that did not complete.
+func result(order entity.Order, status entity.OrderStatus, err error) entity.OrderResult {
+	res := entity.OrderResult{
+		OrderID:  order.ID,
+		Drink:    order.Drink,
+		Priority: order.Priority,
+		Status:   status,
+		Error:    err.Error(),
+	}
+	return res.WithDue(order.DueAtMs)
+}
+
+// run holds the state of a single simulation.
+type run struct {
+	ctx     context.Context
+	clock   *clock.Virtual
+	rand    *rand.Rand
+	menu    *menu.Menu
+	events  eventQueue
+	seq     uint64
+	pools   map[entity.EquipmentType]*pool
+	queue   []entity.Order
+	brewing []*orderRun
+	results []entity.OrderResult
+}
+
+// pool hands its idle workers out to the waiting steps by priority, see
+// entity.Priority.QueueKey.
+type pool struct {
+	faults  entity.FaultModel
+	size    int
+	idle    []int
+	wear    []wear // by worker ID
+	broken  int    // workers being repaired
+	waiting waiterQueue
+	seq     uint64
+}
+
+// wear is what a single worker went through since its last maintenance.
+type wear struct {
+	uses           int           // steps since the last cleaning
+	untilBreakdown time.Duration // processing time left before the next breakdown
+}
+
+// down tells whether the steps sent to the pool fail, its workers being all
+// broken down under the BreakdownFail policy.
+func (p *pool) down() bool {
+	return p.faults.OnBreakdown == entity.BreakdownFail && p.broken == p.size
+}
+
+// orderRun tracks a single order of the simulation.
+type orderRun struct {
+	order      entity.Order
+	recipe     []entity.RecipeStep
+	dependents [][]int
+	blockedBy  []int
+	pending    int
+	steps      []entity.StepExecution
+	running    []bool      // by step, whether a worker is on it
+	acquiredAt []time.Time // by step, when its worker took it
+	over       bool        // completed or given up
+}
+
+func (r *run) schedule(at time.Time, fire func()) {
+	r.seq++
+	heap.Push(&r.events, &event{at: at, seq: r.seq, fire: fire})
+}
+
+// nextOrder is run by a barista when they are free to take the next order.
+func (r *run) nextOrder() {
+	if len(r.queue) == 0 {
+		return
+	}
+
+	order := r.queue[0]
+	r.queue = r.queue[1:]
+
+	// checked before the run
+	recipe, _, _ := r.menu.Expand(order)
+	o := &orderRun{
+		order:      order,
+		recipe:     recipe,
+		dependents: make([][]int, len(recipe)),
+		blockedBy:  make([]int, len(recipe)),
+		pending:    len(recipe),
+		steps:      make([]entity.StepExecution, len(recipe)),
+		running:    make([]bool, len(recipe)),
+		acquiredAt: make([]time.Time, len(recipe)),
+	}
+	for i, step := range recipe {
+		o.blockedBy[i] = len(step.DependsOn)
+		for _, dep := range step.DependsOn {
+			o.dependents[dep] = append(o.dependents[dep], i)
+		}
+	}
+	r.brewing = append(r.brewing, o)
+
+	if o.pending == 0 {
+		r.finishOrder(o)
+		return
+	}
+
+	for i := range recipe {
+		if o.blockedBy[i] == 0 && !o.over {
+			r.startStep(o, i)
+		}
+	}
+}
+
+func (r *run) startStep(o *orderRun, i int) {
+	p := r.pools[o.recipe[i].Equipment]
+	queuedAt := r.clock.Now()
+	o.steps[i] = entity.StepExecution{
+		Equipment:   o.recipe[i].Equipment,
+		StartTimeMs: queuedAt.UnixMilli(),
+		QueuedAtMs:  queuedAt.UnixMilli(),
+	}
+
+	if p.down() {
+		r.failOrder(o, fmt.Errorf("submit failed: %w", apperrors.ErrBrokenDown))
+		return
+	}
+	if len(p.idle) == 0 {
+		p.seq++
+		heap.Push(&p.waiting, &waiter{key: o.order.Priority.QueueKey(queuedAt), seq: p.seq, order: o, step: i})
+		return
+	}
+
+	workerID := p.idle[0]
+	p.idle = p.idle[1:]
+	r.acquired(o, i, workerID)
+}
+
+// acquired puts a worker on a step of the order.
+func (r *run) acquired(o *orderRun, i, workerID int) {
+	acquiredAt := r.clock.Now()
+	o.steps[i].AcquiredAtMs = acquiredAt.UnixMilli()
+	o.steps[i].WorkerID = workerID
+	o.running[i] = true
+	o.acquiredAt[i] = acquiredAt
+
+	r.schedule(acquiredAt.Add(o.recipe[i].Duration), func() {
+		if o.running[i] {
+			r.finishStep(o, i)
+		}
+	})
+}
+
+func (r *run) finishStep(o *orderRun, i int) {
+	releasedAt := r.clock.Now()
+	o.running[i] = false
+	o.steps[i].EndTimeMs = releasedAt.UnixMilli()
+	o.steps[i].ReleasedAtMs = releasedAt.UnixMilli()
+
+	r.release(r.pools[o.recipe[i].Equipment], o.steps[i].WorkerID, o.recipe[i].Duration)
+
+	for _, dependent := range o.dependents[i] {
+		o.blockedBy[dependent]--
+		if o.blockedBy[dependent] == 0 && !o.over {
+			r.startStep(o, dependent)
+		}
+	}
+
+	o.pending--
+	if o.pending == 0 && !o.over {
+		r.finishOrder(o)
+	}
+}
+
+// release wears the worker by a step that took busy, then repairs the worker
+// if it broke down and cleans it if it is due before it takes the next step.
+func (r *run) release(p *pool, workerID int, busy time.Duration) {
+	w := &p.wear[workerID]
+	if p.faults.MTBF > 0 {
+		if w.untilBreakdown <= 0 {
+			// breakdowns come at random, at a constant rate over the processing time
+			w.untilBreakdown = time.Duration(r.rand.ExpFloat64() * float64(p.faults.MTBF))
+		}
+		w.untilBreakdown -= busy
+		if w.untilBreakdown <= 0 {
+			p.broken++
+			if p.down() {
+				r.failWaiting(p)
+			}
+			r.schedule(r.clock.Now().Add(p.faults.RepairTime), func() {
+				p.broken--
+				r.clean(p, workerID)
+			})
+			return
+		}
+	}
+
+	r.clean(p, workerID)
+}
+
+func (r *run) clean(p *pool, workerID int) {
+	w := &p.wear[workerID]
+	w.uses++
+	if p.faults.CleanEvery > 0 && w.uses >= p.faults.CleanEvery {
+		w.uses = 0
+		r.schedule(r.clock.Now().Add(p.faults.CleaningTime), func() {
+			r.free(p, workerID)
+		})
+		return
+	}
+
+	r.free(p, workerID)
+}
+
+// free hands the worker to the first step waiting for it, if any.
+func (r *run) free(p *pool, workerID int) {
+	for p.waiting.Len() > 0 {
+		next := heap.Pop(&p.waiting).(*waiter)
+		if next.order.over {
+			continue
+		}
+		r.acquired(next.order, next.step, workerID)
+		return
+	}
+
+	p.idle = append(p.idle, workerID)
+}
+
+// failWaiting fails the orders of the steps waiting on a pool that went down.
+func (r *run) failWaiting(p *pool) {
+	waiting := p.waiting
+	p.waiting = nil
+	for _, w := range waiting {
+		r.failOrder(w.order, fmt.Errorf("submit failed: %w", apperrors.ErrBrokenDown))
+	}
+}
+
+// failOrder gives the order up, its steps being processed giving their
+// workers back right away, and lets its barista take the next order.
+func (r *run) failOrder(o *orderRun, err error) {
+	if o.over {
+		return
+	}
+
+	o.over = true
+	for i, running := range o.running {
+		if !running {
+			continue
+		}
+		o.running[i] = false
+		r.release(r.pools[o.recipe[i].Equipment], o.steps[i].WorkerID, r.clock.Now().Sub(o.acquiredAt[i]))
+	}
+
+	r.results = append(r.results, result(o.order, entity.OrderStatusFailed, err))
+	r.nextOrder()
+}
+
+func (r *run) finishOrder(o *orderRun) {
+	o.over = true
+	res := entity.OrderResult{
+		OrderID:  o.order.ID,
+		Drink:    o.order.Drink,
+		Priority: o.order.Priority,
+		Status:   entity.OrderStatusCompleted,
+		Steps:    o.steps,
+	}
+	r.results = append(r.results, res.WithDue(o.order.DueAtMs))
+	r.nextOrder()
+}
+
+// expire ends the run once ctx is done: the orders being brewed are
+// cancelled and the ones not picked up yet are left behind.
+func (r *run) expire() {
+	for _, o := range r.brewing {
+		if o.over {
+			continue
+		}
+		o.over = true
+		r.results = append(r.results, result(o.order, entity.OrderStatusCancelled, fmt.Errorf("submit failed: %w", apperrors.ErrJobCancelled)))
+	}
+
+	err := r.ctx.Err()
+	if err == nil {
+		err = context.DeadlineExceeded
+	}
+	for _, order := range r.queue {
+		r.results = append(r.results, result(order, entity.OrderStatusCancelled, err))
+	}
+	r.queue = nil
+	r.events = nil
+}
+
+type event struct {
+	at   time.Time
+	seq  uint64
+	fire func()
+}
+
+// eventQueue is a min-heap of events by time, then by scheduling sequence so
+// that simultaneous events always fire in the same order.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if !q[i].at.Equal(q[j].at) {
+		return q[i].at.Before(q[j].at)
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x any) { *q = append(*q, x.(*event)) }
+
+func (q *eventQueue) Pop() any {
+	old := *q
+	ev := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return ev
+}
+
+type waiter struct {
+	key   int64
+	seq   uint64
+	order *orderRun
+	step  int
+}
+
+// waiterQueue is a min-heap of the steps waiting for a worker by key, then by
+// arrival.
+type waiterQueue []*waiter
+
+func (q waiterQueue) Len() int { return len(q) }
+
+func (q waiterQueue) Less(i, j int) bool {
+	if q[i].key != q[j].key {
+		return q[i].key < q[j].key
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q waiterQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *waiterQueue) Push(x any) { *q = append(*q, x.(*waiter)) }
+
+func (q *waiterQueue) Pop() any {
+	old := *q
+	w := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return w
+}
@@ -0,0 +1,202 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+	"gopher-cafe/internal/menu"
+)
+
+var recipes = map[entity.DrinkType][]entity.RecipeStep{
+	entity.DrinkEspresso: {
+		{Equipment: entity.EquipGrinder, Duration: 5 * time.Millisecond},
+		{Equipment: entity.EquipEspressoMachine, Duration: 8 * time.Millisecond, DependsOn: []int{0}},
+	},
+	entity.DrinkLatte: {
+		{Equipment: entity.EquipGrinder, Duration: 5 * time.Millisecond},
+		{Equipment: entity.EquipEspressoMachine, Duration: 8 * time.Millisecond, DependsOn: []int{0}},
+		{Equipment: entity.EquipMilkSteamer, Duration: 15 * time.Millisecond},
+	},
+	entity.DrinkFrappe: {
+		{Equipment: entity.EquipGrinder, Duration: 5 * time.Millisecond},
+		{Equipment: entity.EquipBlender, Duration: 12 * time.Millisecond, DependsOn: []int{0}},
+	},
+	entity.DrinkMatcha: {
+		{Equipment: entity.EquipGrinder, Duration: 5 * time.Millisecond},
+		{Equipment: entity.EquipMilkSteamer, Duration: 15 * time.Millisecond},
+		{Equipment: entity.EquipWhisk, Duration: 3 * time.Millisecond, DependsOn: []int{0, 1}},
+	},
+}
+
+var workers = map[entity.EquipmentType]uint8{
+	entity.EquipEspressoMachine: 2,
+	entity.EquipGrinder:         1,
+	entity.EquipMilkSteamer:     1,
+	entity.EquipBlender:         1,
+	entity.EquipWhisk:           2,
+}
+
+func TestEngineRun(t *testing.T) {
+	drinks := []entity.DrinkType{entity.DrinkLatte, entity.DrinkEspresso, entity.DrinkMatcha, entity.DrinkFrappe}
+	orders := make([]entity.Order, 10_000)
+	for i := range orders {
+		orders[i] = entity.Order{ID: int64(i + 1), Drink: drinks[i%len(drinks)]}
+	}
+
+	m, err := menu.New(recipes)
+	assert.NoError(t, err)
+
+	engine := NewEngine(workers, nil, m)
+	start := time.UnixMilli(0)
+
+	began := time.Now()
+	results := engine.Run(t.Context(), start, orders, 4)
+	elapsed := time.Since(began)
+
+	assert.Len(t, results, len(orders))
+	for _, res := range results {
+		assert.Equal(t, entity.OrderStatusCompleted, res.Status)
+	}
+	assert.Less(t, elapsed, time.Second)
+
+	// every order needs the single grinder for 5ms
+	last := results[len(results)-1]
+	assert.GreaterOrEqual(t, last.Steps[len(last.Steps)-1].EndTimeMs, int64(5*len(orders)))
+
+	again := engine.Run(t.Context(), start, orders, 4)
+	assert.Equal(t, results, again)
+}
+
+func TestEngineRunMissingEquipment(t *testing.T) {
+	m, err := menu.New(recipes)
+	assert.NoError(t, err)
+
+	engine := NewEngine(map[entity.EquipmentType]uint8{entity.EquipGrinder: 1}, nil, m)
+
+	results := engine.Run(t.Context(), time.UnixMilli(0), []entity.Order{
+		{ID: 1, Drink: entity.DrinkEspresso},
+		{ID: 2, Drink: entity.DrinkUnspecified},
+	}, 1)
+
+	assert.Len(t, results, 2)
+	for _, res := range results {
+		assert.Equal(t, entity.OrderStatusFailed, res.Status)
+		assert.NotEmpty(t, res.Error)
+		assert.Empty(t, res.Steps)
+	}
+}
+
+func TestEngineRunPriority(t *testing.T) {
+	m, err := menu.New(recipes)
+	assert.NoError(t, err)
+
+	engine := NewEngine(workers, nil, m)
+
+	// all the orders want the single grinder at once, order 1 gets it first and
+	// the others wait for it by priority
+	results := engine.Run(t.Context(), time.UnixMilli(0), []entity.Order{
+		{ID: 1, Drink: entity.DrinkEspresso},
+		{ID: 2, Drink: entity.DrinkEspresso},
+		{ID: 3, Drink: entity.DrinkEspresso, Priority: entity.PriorityVIP},
+		{ID: 4, Drink: entity.DrinkEspresso, Priority: entity.PriorityMobile},
+	}, 4)
+
+	wantGrinderAt := map[int64]int64{1: 0, 3: 5, 4: 10, 2: 15}
+	assert.Len(t, results, len(wantGrinderAt))
+	for _, res := range results {
+		assert.Equal(t, entity.OrderStatusCompleted, res.Status)
+		assert.Equal(t, wantGrinderAt[res.OrderID], res.Steps[0].AcquiredAtMs, "order %d", res.OrderID)
+	}
+}
+
+func TestEngineRunFaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		faults entity.FaultModel
+		// when the grinder was acquired by order, and the error of the orders
+		// that failed
+		wantGrinderAt map[int64]int64
+		wantErr       map[int64]error
+	}{
+		{
+			name:          "cleaned after every step",
+			faults:        entity.FaultModel{CleanEvery: 1, CleaningTime: 10 * time.Millisecond, OnBreakdown: entity.BreakdownWait},
+			wantGrinderAt: map[int64]int64{1: 0, 2: 15, 3: 30},
+		},
+		{
+			name: "waits for the repair",
+			// breaks down after every step
+			faults:        entity.FaultModel{MTBF: time.Nanosecond, RepairTime: 20 * time.Millisecond, OnBreakdown: entity.BreakdownWait},
+			wantGrinderAt: map[int64]int64{1: 0, 2: 25, 3: 50},
+		},
+		{
+			name:          "fails while broken down",
+			faults:        entity.FaultModel{MTBF: time.Nanosecond, RepairTime: time.Minute, OnBreakdown: entity.BreakdownFail},
+			wantGrinderAt: map[int64]int64{1: 0},
+			wantErr:       map[int64]error{2: apperrors.ErrBrokenDown, 3: apperrors.ErrBrokenDown},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := menu.New(recipes)
+			assert.NoError(t, err)
+
+			engine := NewEngine(workers, map[entity.EquipmentType]entity.FaultModel{entity.EquipGrinder: test.faults}, m)
+
+			// the orders wait for the single grinder
+			results := engine.Run(t.Context(), time.UnixMilli(0), []entity.Order{
+				{ID: 1, Drink: entity.DrinkEspresso},
+				{ID: 2, Drink: entity.DrinkEspresso},
+				{ID: 3, Drink: entity.DrinkEspresso},
+			}, 3)
+
+			assert.Len(t, results, 3)
+			for _, res := range results {
+				if wantErr, ok := test.wantErr[res.OrderID]; ok {
+					assert.Equal(t, entity.OrderStatusFailed, res.Status, "order %d", res.OrderID)
+					assert.Contains(t, res.Error, wantErr.Error(), "order %d", res.OrderID)
+					continue
+				}
+				assert.Equal(t, entity.OrderStatusCompleted, res.Status, "order %d", res.OrderID)
+				assert.Equal(t, test.wantGrinderAt[res.OrderID], res.Steps[0].AcquiredAtMs, "order %d", res.OrderID)
+			}
+		})
+	}
+}
+
+func TestEngineRunDeadline(t *testing.T) {
+	m, err := menu.New(recipes)
+	assert.NoError(t, err)
+
+	engine := NewEngine(workers, nil, m)
+	orders := []entity.Order{
+		{ID: 1, Drink: entity.DrinkEspresso},
+		{ID: 2, Drink: entity.DrinkEspresso},
+		{ID: 3, Drink: entity.DrinkEspresso},
+	}
+
+	// the single barista brews order 1 from 0 to 13ms and is on order 2 at
+	// the deadline
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	results := engine.Run(ctx, time.UnixMilli(0), orders, 1)
+	assert.Len(t, results, 3)
+	assert.Equal(t, entity.OrderStatusCompleted, results[0].Status)
+	assert.Equal(t, entity.OrderStatusCancelled, results[1].Status)
+	assert.Contains(t, results[1].Error, apperrors.ErrJobCancelled.Error())
+	assert.Equal(t, entity.OrderStatusCancelled, results[2].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), results[2].Error)
+
+	// nothing is brewed once ctx is done
+	cancel()
+	for _, res := range engine.Run(ctx, time.UnixMilli(0), orders, 1) {
+		assert.Equal(t, entity.OrderStatusCancelled, res.Status)
+		assert.Equal(t, context.Canceled.Error(), res.Error)
+	}
+}
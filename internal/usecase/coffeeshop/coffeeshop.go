@@ -2,64 +2,195 @@ package coffeeshop
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"gopher-cafe/internal/clock"
+	"gopher-cafe/internal/inventory"
+	"gopher-cafe/internal/menu"
+	"gopher-cafe/internal/orderstore"
+	"gopher-cafe/internal/simulation"
 	"gopher-cafe/internal/worker"
+	"maps"
 	"sync"
-	"time"
+	"sync/atomic"
 
 	entity "gopher-cafe/internal/entity/coffeeshop"
-
-	"github.com/ajaibid/coin-common-golang/logger"
+	apperrors "gopher-cafe/internal/errors"
 )
 
 type CoffeeshopUsecase struct {
 	equipPoolManager *worker.EquipPoolManager
+	menu             atomic.Pointer[menu.Menu]
+	inventory        *inventory.Inventory
+	tickets          *orderstore.Store
 	metrics          *entity.OrderMetrics
+	scheduling       entity.SchedulingStrategy
+	clock            clock.Clock
+	simulate         bool
+
+	// the tickets brewing in the background, cancelled by stopTickets
+	brewing        sync.WaitGroup
+	ticketsStopped context.Context
+	stopTickets    context.CancelFunc
+}
+
+type Option func(*CoffeeshopUsecase)
+
+// WithScheduling sets the strategy used by requests that do not pick one.
+func WithScheduling(strategy entity.SchedulingStrategy) Option {
+	return func(u *CoffeeshopUsecase) {
+		u.scheduling = strategy
+	}
 }
 
-func NewCoffeeshopUsecase(manager *worker.EquipPoolManager, metrics *entity.OrderMetrics) *CoffeeshopUsecase {
-	return &CoffeeshopUsecase{
+// WithClock sets the clock the step timestamps are taken from.
+func WithClock(clk clock.Clock) Option {
+	return func(u *CoffeeshopUsecase) {
+		u.clock = clk
+	}
+}
+
+// WithSimulation makes ExecuteBrew run on the discrete-event simulation engine
+// instead of the worker pools, starting the virtual time at the usecase clock.
+// The engine takes the size and the faults of the pools.
+func WithSimulation() Option {
+	return func(u *CoffeeshopUsecase) {
+		u.simulate = true
+	}
+}
+
+func NewCoffeeshopUsecase(manager *worker.EquipPoolManager, m *menu.Menu, metrics *entity.OrderMetrics, opts ...Option) *CoffeeshopUsecase {
+	u := &CoffeeshopUsecase{
 		equipPoolManager: manager,
 		metrics:          metrics,
+		inventory:        inventory.New(),
+		scheduling:       entity.SchedulingFIFO,
+		clock:            clock.New(),
 	}
+	u.ticketsStopped, u.stopTickets = context.WithCancel(context.Background())
+	u.menu.Store(m)
+	u.inventory.Stock(m.Inventory())
+
+	for _, opt := range opts {
+		opt(u)
+	}
+	u.tickets = orderstore.New(u.clock, ticketRetention)
+
+	return u
 }
 
-func (u *CoffeeshopUsecase) ExecuteBrew(ctx context.Context, orders []entity.Order, baristas int) []entity.OrderResult {
-	orderInputChan := make(chan entity.Order, len(orders))
+// ExecuteBrew brews the orders with the given number of baristas, who pick the
+// orders up in the sequence decided by strategy. An empty strategy falls back
+// to the default one. Every order gets a result telling whether it completed,
+// unless the request is invalid, in which case nothing is brewed and a
+// ValidationError is returned. The orders estimated not to finish before the
+// deadline of ctx fail without being brewed.
+func (u *CoffeeshopUsecase) ExecuteBrew(ctx context.Context, orders []entity.Order, baristas int, strategy entity.SchedulingStrategy) ([]entity.OrderResult, error) {
+	return u.StreamBrew(ctx, orders, baristas, strategy, nil)
+}
 
-	for _, order := range orders {
-		orderInputChan <- order
-		logger.Debugf("Order %d submitted", order.ID)
+// StreamBrew brews like ExecuteBrew and calls onEvent each time a step starts
+// or finishes, each time an order completes or fails and each time an order
+// changes state. The orders that got ready are picked up by the caller along
+// with the results. onEvent is never called concurrently.
+func (u *CoffeeshopUsecase) StreamBrew(ctx context.Context, orders []entity.Order, baristas int, strategy entity.SchedulingStrategy, onEvent func(entity.BrewEvent)) ([]entity.OrderResult, error) {
+	results, sink, err := u.brew(ctx, orders, baristas, strategy, onEvent)
+	if err != nil {
+		return nil, err
+	}
+	sink.pickUp(u.clock.Now().UnixMilli())
+
+	return results, nil
+}
+
+// brew brews the orders and returns their results along with the sink that
+// drove their lifecycle, which leaves the ready orders to be picked up.
+func (u *CoffeeshopUsecase) brew(ctx context.Context, orders []entity.Order, baristas int, strategy entity.SchedulingStrategy, onEvent func(entity.BrewEvent)) ([]entity.OrderResult, *eventSink, error) {
+	// the whole request is brewed with the menu of the time it came in
+	m := u.menu.Load()
+
+	if err := validateBrew(m, orders, baristas); err != nil {
+		return nil, nil, err
+	}
+
+	sink := newEventSink(ctx, orders, u.clock.Now().UnixMilli(), u.metrics, onEvent)
+	scheduled := u.scheduler(ctx, strategy).Schedule(orders, m)
+
+	// the orders that cannot make it in time are not brewed at all
+	scheduled, refused := u.refuseLate(ctx, m, scheduled, baristas)
+
+	if u.simulate {
+		for _, order := range scheduled {
+			sink.queue(order.ID, u.clock.Now().UnixMilli())
+		}
+		return u.simulateBrew(ctx, m, scheduled, refused, baristas, sink), sink, nil
 	}
-	close(orderInputChan)
 
 	orderResultChan := make(chan entity.OrderResult, len(orders))
 
+	for _, res := range refused {
+		debugf(ctx, "Order %d refused: %s", res.OrderID, res.Error)
+		orderResultChan <- res
+		sink.emit(entity.BrewEvent{
+			Type:    entity.BrewEventOrderFailed,
+			OrderID: res.OrderID,
+			TimeMs:  u.clock.Now().UnixMilli(),
+			Result:  res,
+		})
+	}
+
+	orderInputChan := make(chan entity.Order, len(scheduled))
+
+	for _, order := range scheduled {
+		orderInputChan <- order
+		sink.queue(order.ID, u.clock.Now().UnixMilli())
+		debugf(ctx, "Order %d submitted", order.ID)
+	}
+	close(orderInputChan)
+
 	wg := sync.WaitGroup{}
 
 	wg.Add(baristas)
 	for i := range baristas {
 		go func() {
 			defer wg.Done()
-			logger.Debugf("Baristas: %d start working", i)
+			debugf(ctx, "Baristas: %d start working", i)
 			for {
 				select {
 				case <-ctx.Done():
-					logger.Debugf("Baristas: %d got context done, %v", i, ctx.Err())
+					debugf(ctx, "Baristas: %d got context done, %v", i, ctx.Err())
 					return
 				case input, ok := <-orderInputChan:
 					if !ok {
-						logger.Debugf("Baristas: %d got channel closed", i)
+						debugf(ctx, "Baristas: %d got channel closed", i)
 						return
 					}
-					logger.Debugf("Baristas: %d executing order: %d", i, input.ID)
-					res, err := u.processOrder(input)
+					debugf(ctx, "Baristas: %d executing order: %d", i, input.ID)
+					res, err := u.processOrder(ctx, m, input, sink)
 					if err != nil {
-						logger.Errorf("Baristas: %d processing order %d failed: %s", i, input.ID, err)
+						res = failedResult(input, err)
+						if res.Status == entity.OrderStatusCancelled {
+							debugf(ctx, "Baristas: %d order %d cancelled: %s", i, input.ID, err)
+						} else {
+							errorf(ctx, "Baristas: %d processing order %d failed: %s", i, input.ID, err)
+						}
+						orderResultChan <- res
+						sink.emit(entity.BrewEvent{
+							Type:    entity.BrewEventOrderFailed,
+							OrderID: res.OrderID,
+							TimeMs:  u.clock.Now().UnixMilli(),
+							Result:  res,
+						})
 						continue
 					}
 					orderResultChan <- res
 					u.recordOrderStats(res)
+					sink.emit(entity.BrewEvent{
+						Type:    entity.BrewEventOrderCompleted,
+						OrderID: res.OrderID,
+						TimeMs:  u.clock.Now().UnixMilli(),
+						Result:  res,
+					})
 				}
 			}
 		}()
@@ -67,56 +198,376 @@ func (u *CoffeeshopUsecase) ExecuteBrew(ctx context.Context, orders []entity.Ord
 
 	go func() {
 		wg.Wait()
+		// the baristas stopped early, the orders they left behind are
+		// reported as cancelled
+		for order := range orderInputChan {
+			res := failedResult(order, ctx.Err())
+			orderResultChan <- res
+			sink.emit(entity.BrewEvent{
+				Type:    entity.BrewEventOrderFailed,
+				OrderID: res.OrderID,
+				TimeMs:  u.clock.Now().UnixMilli(),
+				Result:  res,
+			})
+		}
 		close(orderResultChan)
 	}()
 
 	results := make([]entity.OrderResult, 0, len(orders))
 
+	completed := 0
 	for result := range orderResultChan {
 		results = append(results, result)
+		u.metrics.RecordDeadline(result)
+		if result.Status == entity.OrderStatusCompleted {
+			completed++
+		}
+	}
+
+	if completed == len(orders) {
+		u.metrics.RecordTotalRequests(1)
+	}
+
+	debugf(ctx, "Finish execute brew : %d, %d", len(orders), baristas)
+	return results, sink, nil
+}
+
+func validateBrew(m *menu.Menu, orders []entity.Order, baristas int) error {
+	if baristas < 1 {
+		return &apperrors.ValidationError{Violations: []apperrors.Violation{
+			{Field: "baristas", Reason: "at least 1 barista is required"},
+		}}
+	}
+
+	var violations []apperrors.Violation
+
+	var verr *apperrors.ValidationError
+	if err := entity.ValidateOrders(orders); errors.As(err, &verr) {
+		violations = append(violations, verr.Violations...)
+	}
+
+	for i, order := range orders {
+		if order.Drink == entity.DrinkUnspecified {
+			continue
+		}
+		_, _, err := m.Expand(order)
+		switch {
+		case errors.Is(err, apperrors.ErrUnknownRecipe):
+			violations = append(violations, apperrors.Violation{
+				Field:  fmt.Sprintf("orders[%d].drink", i),
+				Reason: fmt.Sprintf("%s is not on the menu", order.Drink),
+			})
+		case errors.Is(err, apperrors.ErrBadModifiers):
+			violations = append(violations, apperrors.Violation{
+				Field:  fmt.Sprintf("orders[%d].modifiers", i),
+				Reason: err.Error(),
+			})
+		}
+	}
+	if len(violations) > 0 {
+		return &apperrors.ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// simulateBrew brews the orders on the simulation engine, the refused ones
+// failing at the start.
+func (u *CoffeeshopUsecase) simulateBrew(ctx context.Context, m *menu.Menu, orders []entity.Order, refused []entity.OrderResult, baristas int, sink *eventSink) []entity.OrderResult {
+	engine := simulation.NewEngine(u.equipPoolManager.Workers(), u.equipPoolManager.Faults(), m)
+
+	// the ingredients are taken in the scheduled order, the orders short of
+	// one are not brewed
+	var (
+		reserved []entity.Order
+		short    []entity.OrderResult
+	)
+	needs := make(map[int64]map[entity.Ingredient]int64, len(orders))
+	for _, order := range orders {
+		_, needs[order.ID], _ = m.Expand(order)
+		if err := u.inventory.Reserve(needs[order.ID]); err != nil {
+			short = append(short, failedResult(order, err))
+			continue
+		}
+		reserved = append(reserved, order)
+	}
+
+	start := u.clock.Now()
+	results := engine.Run(ctx, start, reserved, baristas)
+	for _, res := range results {
+		if res.Status != entity.OrderStatusCompleted {
+			u.inventory.Release(needs[res.OrderID])
+		}
 	}
+	results = append(results, short...)
+	results = append(results, refused...)
 
-	if len(results) == len(orders) {
+	completed := 0
+	for _, res := range results {
+		u.metrics.RecordDeadline(res)
+		if res.Status != entity.OrderStatusCompleted {
+			errorf(ctx, "Simulating order %d failed: %s", res.OrderID, res.Error)
+			continue
+		}
+		u.recordOrderStats(res)
+		completed++
+	}
+	if completed == len(results) {
 		u.metrics.RecordTotalRequests(1)
 	}
 
-	logger.Debugf("Finish execute brew : %d, %d", len(orders), baristas)
+	for _, ev := range eventsFromResults(start.UnixMilli(), results) {
+		sink.emit(ev)
+	}
+
+	debugf(ctx, "Finish simulate brew : %d, %d", len(orders), baristas)
 	return results
 }
 
-func (u *CoffeeshopUsecase) processOrder(order entity.Order) (entity.OrderResult, error) {
+// processOrder runs every step of the order recipe, expanded with its
+// modifiers, as soon as the steps it depends on are done, so independent steps
+// share the equipment pools at the same time. The first failing step cancels
+// the rest of the order.
+func (u *CoffeeshopUsecase) processOrder(ctx context.Context, m *menu.Menu, order entity.Order, sink *eventSink) (entity.OrderResult, error) {
 	var emptyResult entity.OrderResult
 
-	recipe := entity.Recipes[order.Drink]
-	res := entity.OrderResult{OrderID: order.ID}
+	recipe, needs, err := m.Expand(order)
+	if err != nil {
+		return emptyResult, err
+	}
+
+	// the ingredients go back in stock unless the drink is made
+	if err := u.inventory.Reserve(needs); err != nil {
+		return emptyResult, err
+	}
+	made := false
+	defer func() {
+		if !made {
+			u.inventory.Release(needs)
+		}
+	}()
+
+	done := make([]chan struct{}, len(recipe))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		stepErr error
+	)
+
+	// steps are indexed like the recipe to keep the result order stable
+	steps := make([]entity.StepExecution, len(recipe))
 
-	for _, step := range recipe {
-		startStep := time.Now().UnixMilli()
+	wg.Add(len(recipe))
+	for i, step := range recipe {
+		go func() {
+			defer wg.Done()
 
-		pool, err := u.equipPoolManager.GetWorkerPool(step.Equipment)
-		if err != nil {
-			return emptyResult, fmt.Errorf("get worker pool failed: %v", err)
+			for _, dep := range step.DependsOn {
+				select {
+				case <-done[dep]:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			exec, err := u.processStep(ctx, order, i, step, sink)
+			if err != nil {
+				errOnce.Do(func() {
+					stepErr = err
+					cancel()
+				})
+				return
+			}
+
+			steps[i] = exec
+			close(done[i])
+		}()
+	}
+	wg.Wait()
+
+	if stepErr != nil {
+		return emptyResult, stepErr
+	}
+	for i := range done {
+		select {
+		case <-done[i]:
+		default:
+			// gave up waiting on its dependencies
+			return emptyResult, apperrors.ErrJobCancelled
 		}
+	}
 
-		_, err = pool.Submit(worker.Job{
-			OrderID: order.ID,
-			Timer:   step.Duration,
-		})
+	made = true
+	res := entity.OrderResult{OrderID: order.ID, Drink: order.Drink, Priority: order.Priority, Status: entity.OrderStatusCompleted, Steps: steps}
+	return res.WithDue(order.DueAtMs), nil
+}
+
+// failedResult reports an order that did not complete. Orders given up on
+// because the request was cancelled or timed out are told apart from the ones
+// that failed.
+func failedResult(order entity.Order, err error) entity.OrderResult {
+	status := entity.OrderStatusFailed
+	if errors.Is(err, apperrors.ErrJobCancelled) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		status = entity.OrderStatusCancelled
+	}
+
+	res := entity.OrderResult{
+		OrderID:  order.ID,
+		Drink:    order.Drink,
+		Priority: order.Priority,
+		Status:   status,
+		Error:    err.Error(),
+	}
+	return res.WithDue(order.DueAtMs)
+}
+
+func (u *CoffeeshopUsecase) processStep(ctx context.Context, order entity.Order, index int, step entity.RecipeStep, sink *eventSink) (entity.StepExecution, error) {
+	var emptyStep entity.StepExecution
+
+	startStep := u.clock.Now().UnixMilli()
+	sink.emit(entity.BrewEvent{
+		Type:      entity.BrewEventStepStarted,
+		OrderID:   order.ID,
+		TimeMs:    startStep,
+		StepIndex: index,
+		Step:      entity.StepExecution{Equipment: step.Equipment, StartTimeMs: startStep},
+	})
+
+	pool, err := u.equipPoolManager.GetWorkerPool(step.Equipment)
+	if err != nil {
+		return emptyStep, fmt.Errorf("get worker pool failed: %w", err)
+	}
+
+	out, err := pool.Submit(ctx, worker.Job{
+		OrderID:  order.ID,
+		Timer:    step.Duration,
+		Priority: order.Priority,
+	})
+
+	if err != nil {
+		return emptyStep, fmt.Errorf("submit failed: %w", err)
+	}
+
+	endStep := u.clock.Now().UnixMilli()
+
+	exec := entity.StepExecution{
+		Equipment:    step.Equipment,
+		StartTimeMs:  startStep,
+		EndTimeMs:    endStep,
+		QueuedAtMs:   out.QueuedAt.UnixMilli(),
+		AcquiredAtMs: out.AcquiredAt.UnixMilli(),
+		ReleasedAtMs: out.ReleasedAt.UnixMilli(),
+		WorkerID:     out.WorkerID,
+	}
+	sink.emit(entity.BrewEvent{
+		Type:      entity.BrewEventStepFinished,
+		OrderID:   order.ID,
+		TimeMs:    endStep,
+		StepIndex: index,
+		Step:      exec,
+	})
 
-		if err != nil {
-			return emptyResult, fmt.Errorf("submit failed: %v", err)
+	return exec, nil
+}
+
+// ApplyMenu resizes the equipment pools whose workers m changes from the
+// previous menu, stocks the ingredients m adds and makes m the menu of the
+// requests to come. A pool the menu leaves as it was keeps the size it was
+// given through ResizeEquipment. Requests already brewing keep their menu,
+// and retired workers finish their current step first. m is rejected, with
+// nothing changed, if it uses an equipment without workers or sets up one
+// without a pool.
+func (u *CoffeeshopUsecase) ApplyMenu(m *menu.Menu) error {
+	previous := u.menu.Load().Workers()
+	resized := make(map[entity.EquipmentType]uint8)
+	for equipType, n := range m.Workers() {
+		if previous[equipType] != n {
+			resized[equipType] = n
 		}
+	}
 
-		endStep := time.Now().UnixMilli()
+	var errs []error
+	for equipType := range resized {
+		if _, err := u.equipPoolManager.GetWorkerPool(equipType); err != nil {
+			errs = append(errs, fmt.Errorf("resize %s: %w", equipType, err))
+		}
+	}
+	for equipType := range m.Faults() {
+		if _, err := u.equipPoolManager.GetWorkerPool(equipType); err != nil {
+			errs = append(errs, fmt.Errorf("set faults of %s: %w", equipType, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
 
-		res.Steps = append(res.Steps, entity.StepExecution{
-			Equipment:   step.Equipment,
-			StartTimeMs: startStep,
-			EndTimeMs:   endStep,
-		})
+	workers := u.equipPoolManager.Workers()
+	maps.Copy(workers, resized)
+	if err := m.Validate(workers); err != nil {
+		return err
+	}
+
+	for equipType, n := range resized {
+		if err := u.equipPoolManager.Resize(equipType, n); err != nil {
+			return fmt.Errorf("resize %s: %w", equipType, err)
+		}
+	}
+	for equipType, faults := range m.Faults() {
+		if err := u.equipPoolManager.SetFaults(equipType, faults); err != nil {
+			return fmt.Errorf("set faults of %s: %w", equipType, err)
+		}
+	}
+	u.menu.Store(m)
+	u.inventory.Stock(m.Inventory())
+
+	return nil
+}
+
+// ResizeEquipment sets the number of workers of an equipment and returns the
+// number it had before.
+func (u *CoffeeshopUsecase) ResizeEquipment(equipType entity.EquipmentType, workers uint8) (uint8, error) {
+	pool, err := u.equipPoolManager.GetWorkerPool(equipType)
+	if err != nil {
+		return 0, err
 	}
 
-	return res, nil
+	previous := pool.Size()
+	if err := pool.Resize(workers); err != nil {
+		return 0, err
+	}
+
+	return previous, nil
+}
+
+// Restock adds quantity to the stock of an ingredient and returns its new
+// level.
+func (u *CoffeeshopUsecase) Restock(ingredient entity.Ingredient, quantity int64) (entity.IngredientLevel, error) {
+	return u.inventory.Restock(ingredient, quantity)
+}
+
+// GetInventory returns the level of every ingredient in stock.
+func (u *CoffeeshopUsecase) GetInventory() []entity.IngredientLevel {
+	return u.inventory.Levels()
+}
+
+func (u *CoffeeshopUsecase) scheduler(ctx context.Context, strategy entity.SchedulingStrategy) Scheduler {
+	if strategy == "" {
+		strategy = u.scheduling
+	}
+
+	if scheduler, ok := schedulers[strategy]; ok {
+		return scheduler
+	}
+
+	errorf(ctx, "Unknown scheduling strategy %q, falling back to %s", strategy, entity.SchedulingFIFO)
+	return schedulers[entity.SchedulingFIFO]
 }
 
 func (u *CoffeeshopUsecase) recordOrderStats(res entity.OrderResult) {
@@ -126,3 +577,30 @@ func (u *CoffeeshopUsecase) recordOrderStats(res entity.OrderResult) {
 func (u *CoffeeshopUsecase) GetStats() (int64, int64, int64) {
 	return u.metrics.GetStats()
 }
+
+// GetEquipmentStats returns the utilization of every equipment pool.
+func (u *CoffeeshopUsecase) GetEquipmentStats() map[entity.EquipmentType]entity.EquipmentStats {
+	pools := u.equipPoolManager.Stats()
+
+	stats := make(map[entity.EquipmentType]entity.EquipmentStats, len(pools))
+	for equipType, pool := range pools {
+		stats[equipType] = entity.EquipmentStats{
+			Workers:        pool.Workers,
+			BusyWorkers:    pool.Busy,
+			QueueDepth:     pool.QueueDepth,
+			JobsCompleted:  pool.JobsCompleted,
+			WaitTime:       pool.WaitTime,
+			ProcessingTime: pool.ProcessingTime,
+			WorkerBusyTime: pool.WorkerBusyTime,
+			Utilization:    pool.Utilization(),
+			Broken:         pool.Broken,
+			Cleaning:       pool.Cleaning,
+			Breakdowns:     pool.Breakdowns,
+			Cleanings:      pool.Cleanings,
+			RepairTime:     pool.RepairTime,
+			CleaningTime:   pool.CleaningTime,
+		}
+	}
+
+	return stats
+}
@@ -2,7 +2,6 @@ package coffeeshop
 
 import (
 	entity "gopher-cafe/internal/entity/coffeeshop"
-	"gopher-cafe/internal/worker"
 	"testing"
 )
 
@@ -15,17 +14,11 @@ import (
 //BenchmarkExecuteBrew-8   	     100	 107174945 ns/op	    7355 B/op	     150 allocs/op
 
 func BenchmarkExecuteBrew(b *testing.B) {
-	ew := worker.EquipmentWorkers
-
-	manager := worker.NewEquipPoolManager(uint8(len(ew)))
-	for k, v := range ew {
-		manager.Register(k, v)
-	}
-	manager.StartAll()
+	manager := newTestManager(b)
 
 	metrics := entity.NewOrderMetrics()
 
-	usecase := NewCoffeeshopUsecase(manager, metrics)
+	usecase := NewCoffeeshopUsecase(manager, newTestMenu(b), metrics)
 
 	orders := []entity.Order{
 		{
@@ -64,6 +57,38 @@ func BenchmarkExecuteBrew(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		usecase.ExecuteBrew(b.Context(), orders, 2)
+		_, _ = usecase.ExecuteBrew(b.Context(), orders, 2, "")
+	}
+}
+
+// BenchmarkExecuteBrewScheduling reports the p90 order duration of every
+// scheduling strategy brewing the same orders.
+func BenchmarkExecuteBrewScheduling(b *testing.B) {
+	manager := newTestManager(b)
+
+	drinks := []entity.DrinkType{entity.DrinkLatte, entity.DrinkEspresso, entity.DrinkMatcha, entity.DrinkFrappe}
+	orders := make([]entity.Order, 0, 24)
+	for i := range 24 {
+		orders = append(orders, entity.Order{ID: int64(i + 1), Drink: drinks[i%len(drinks)]})
+	}
+
+	strategies := []entity.SchedulingStrategy{
+		entity.SchedulingFIFO,
+		entity.SchedulingShortestFirst,
+		entity.SchedulingLongestFirst,
+		entity.SchedulingRoundRobin,
+	}
+	for _, strategy := range strategies {
+		b.Run(string(strategy), func(b *testing.B) {
+			metrics := entity.NewOrderMetrics()
+			usecase := NewCoffeeshopUsecase(manager, newTestMenu(b), metrics)
+
+			for i := 0; i < b.N; i++ {
+				_, _ = usecase.ExecuteBrew(b.Context(), orders, 3, strategy)
+			}
+
+			_, _, p90 := usecase.GetStats()
+			b.ReportMetric(float64(p90), "p90-ms")
+		})
 	}
 }
@@ -1,13 +1,63 @@
 package coffeeshop
 
 import (
+	"context"
+	"gopher-cafe/internal/clock"
 	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+	"gopher-cafe/internal/menu"
 	"gopher-cafe/internal/worker"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+func newTestMenu(tb testing.TB) *menu.Menu {
+	tb.Helper()
+
+	m, err := menu.New(map[entity.DrinkType][]entity.RecipeStep{
+		entity.DrinkEspresso: {
+			{Equipment: entity.EquipGrinder, Duration: 5 * time.Millisecond},
+			{Equipment: entity.EquipEspressoMachine, Duration: 8 * time.Millisecond, DependsOn: []int{0}},
+		},
+		entity.DrinkLatte: {
+			{Equipment: entity.EquipGrinder, Duration: 5 * time.Millisecond},
+			{Equipment: entity.EquipEspressoMachine, Duration: 8 * time.Millisecond, DependsOn: []int{0}},
+			{Equipment: entity.EquipMilkSteamer, Duration: 15 * time.Millisecond},
+		},
+		entity.DrinkFrappe: {
+			{Equipment: entity.EquipGrinder, Duration: 5 * time.Millisecond},
+			{Equipment: entity.EquipBlender, Duration: 12 * time.Millisecond, DependsOn: []int{0}},
+		},
+		entity.DrinkMatcha: {
+			{Equipment: entity.EquipGrinder, Duration: 5 * time.Millisecond},
+			{Equipment: entity.EquipMilkSteamer, Duration: 15 * time.Millisecond},
+			{Equipment: entity.EquipWhisk, Duration: 3 * time.Millisecond, DependsOn: []int{0, 1}},
+		},
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return m
+}
+
+// newTestManager returns started pools of the default equipment, stopped when
+// the test is done.
+func newTestManager(tb testing.TB) *worker.EquipPoolManager {
+	tb.Helper()
+
+	manager := worker.NewEquipPoolManager(uint8(len(worker.EquipmentWorkers)), clock.New())
+	for k, v := range worker.EquipmentWorkers {
+		manager.Register(k, v)
+	}
+	manager.StartAll()
+	tb.Cleanup(manager.StopAll)
+
+	return manager
+}
+
 func Test(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -55,18 +105,13 @@ func Test(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			ew := worker.EquipmentWorkers
-
-			manager := worker.NewEquipPoolManager(uint8(len(ew)))
-			for k, v := range ew {
-				manager.Register(k, v)
-			}
-			manager.StartAll()
+			manager := newTestManager(t)
 
 			metrics := entity.NewOrderMetrics()
 
-			usecase := NewCoffeeshopUsecase(manager, metrics)
-			results := usecase.ExecuteBrew(t.Context(), test.orders, test.baristas)
+			usecase := NewCoffeeshopUsecase(manager, newTestMenu(t), metrics)
+			results, err := usecase.ExecuteBrew(t.Context(), test.orders, test.baristas, "")
+			assert.NoError(t, err)
 
 			assert.Len(t, results, len(test.want))
 
@@ -84,3 +129,579 @@ func Test(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessOrderParallelSteps(t *testing.T) {
+	manager := newTestManager(t)
+
+	usecase := NewCoffeeshopUsecase(manager, newTestMenu(t), entity.NewOrderMetrics())
+
+	res, err := usecase.processOrder(t.Context(), newTestMenu(t), entity.Order{ID: 1, Drink: entity.DrinkLatte}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, res.Steps, 3)
+
+	grinder, espresso, steamer := res.Steps[0], res.Steps[1], res.Steps[2]
+	assert.Equal(t, entity.EquipGrinder, grinder.Equipment)
+	assert.Equal(t, entity.EquipEspressoMachine, espresso.Equipment)
+	assert.Equal(t, entity.EquipMilkSteamer, steamer.Equipment)
+
+	// espresso waits for the grinder, the steamer does not wait for anyone
+	assert.GreaterOrEqual(t, espresso.StartTimeMs, grinder.EndTimeMs)
+	assert.LessOrEqual(t, steamer.StartTimeMs, grinder.EndTimeMs)
+
+	for _, step := range res.Steps {
+		assert.LessOrEqual(t, step.QueuedAtMs, step.AcquiredAtMs)
+		assert.LessOrEqual(t, step.AcquiredAtMs, step.ReleasedAtMs)
+		assert.LessOrEqual(t, step.ReleasedAtMs, step.EndTimeMs)
+	}
+}
+
+func TestExecuteBrewSimulation(t *testing.T) {
+	manager := newTestManager(t)
+
+	metrics := entity.NewOrderMetrics()
+
+	usecase := NewCoffeeshopUsecase(manager, newTestMenu(t), metrics,
+		WithClock(clock.NewVirtual(time.UnixMilli(0))),
+		WithSimulation(),
+	)
+
+	orders := []entity.Order{
+		{ID: 1, Drink: entity.DrinkLatte},
+		{ID: 2, Drink: entity.DrinkEspresso},
+		{ID: 3, Drink: entity.DrinkMatcha},
+	}
+	results, err := usecase.ExecuteBrew(t.Context(), orders, 2, entity.SchedulingFIFO)
+	assert.NoError(t, err)
+
+	// order 2 queues for the single grinder while order 1 is using it, and
+	// order 3 is picked up once the first barista is done with order 1
+	want := []entity.OrderResult{
+		{
+			OrderID: 1,
+			Drink:   entity.DrinkLatte,
+			Status:  entity.OrderStatusCompleted,
+			Steps: []entity.StepExecution{
+				{Equipment: entity.EquipGrinder, StartTimeMs: 0, EndTimeMs: 5, QueuedAtMs: 0, AcquiredAtMs: 0, ReleasedAtMs: 5, WorkerID: 0},
+				{Equipment: entity.EquipEspressoMachine, StartTimeMs: 5, EndTimeMs: 13, QueuedAtMs: 5, AcquiredAtMs: 5, ReleasedAtMs: 13, WorkerID: 0},
+				{Equipment: entity.EquipMilkSteamer, StartTimeMs: 0, EndTimeMs: 15, QueuedAtMs: 0, AcquiredAtMs: 0, ReleasedAtMs: 15, WorkerID: 0},
+			},
+		},
+		{
+			OrderID: 2,
+			Drink:   entity.DrinkEspresso,
+			Status:  entity.OrderStatusCompleted,
+			Steps: []entity.StepExecution{
+				{Equipment: entity.EquipGrinder, StartTimeMs: 0, EndTimeMs: 10, QueuedAtMs: 0, AcquiredAtMs: 5, ReleasedAtMs: 10, WorkerID: 0},
+				{Equipment: entity.EquipEspressoMachine, StartTimeMs: 10, EndTimeMs: 18, QueuedAtMs: 10, AcquiredAtMs: 10, ReleasedAtMs: 18, WorkerID: 1},
+			},
+		},
+		{
+			OrderID: 3,
+			Drink:   entity.DrinkMatcha,
+			Status:  entity.OrderStatusCompleted,
+			Steps: []entity.StepExecution{
+				{Equipment: entity.EquipGrinder, StartTimeMs: 15, EndTimeMs: 20, QueuedAtMs: 15, AcquiredAtMs: 15, ReleasedAtMs: 20, WorkerID: 0},
+				{Equipment: entity.EquipMilkSteamer, StartTimeMs: 15, EndTimeMs: 30, QueuedAtMs: 15, AcquiredAtMs: 15, ReleasedAtMs: 30, WorkerID: 0},
+				{Equipment: entity.EquipWhisk, StartTimeMs: 30, EndTimeMs: 33, QueuedAtMs: 30, AcquiredAtMs: 30, ReleasedAtMs: 33, WorkerID: 0},
+			},
+		},
+	}
+	assert.Equal(t, want, results)
+
+	totalRequests, totalOrders, p90 := usecase.GetStats()
+	assert.Equal(t, int64(1), totalRequests)
+	assert.Equal(t, int64(3), totalOrders)
+	assert.Equal(t, int64(18), p90)
+
+	snapshot := metrics.Snapshot()
+	assert.Equal(t, int64(3), snapshot.PriorityDuration[entity.PriorityWalkIn].Count)
+}
+
+func TestSimulationMatchesPools(t *testing.T) {
+	// long enough steps for the scheduling noise of the pools to stay small
+	m, err := menu.New(map[entity.DrinkType][]entity.RecipeStep{
+		entity.DrinkEspresso: {
+			{Equipment: entity.EquipGrinder, Duration: 20 * time.Millisecond},
+			{Equipment: entity.EquipEspressoMachine, Duration: 30 * time.Millisecond, DependsOn: []int{0}},
+		},
+		entity.DrinkLatte: {
+			{Equipment: entity.EquipGrinder, Duration: 20 * time.Millisecond},
+			{Equipment: entity.EquipEspressoMachine, Duration: 30 * time.Millisecond, DependsOn: []int{0}},
+			{Equipment: entity.EquipMilkSteamer, Duration: 40 * time.Millisecond},
+		},
+	})
+	assert.NoError(t, err)
+
+	manager := newTestManager(t)
+
+	// the grinder is still being cleaned when every other order comes in
+	assert.NoError(t, manager.SetFaults(entity.EquipGrinder, entity.FaultModel{
+		CleanEvery:   2,
+		CleaningTime: 80 * time.Millisecond,
+		OnBreakdown:  entity.BreakdownWait,
+	}))
+
+	orders := []entity.Order{
+		{ID: 1, Drink: entity.DrinkEspresso},
+		{ID: 2, Drink: entity.DrinkLatte},
+		{ID: 3, Drink: entity.DrinkEspresso},
+		{ID: 4, Drink: entity.DrinkLatte},
+	}
+
+	simulated, err := NewCoffeeshopUsecase(manager, m, entity.NewOrderMetrics(), WithSimulation()).
+		ExecuteBrew(t.Context(), orders, 1, entity.SchedulingFIFO)
+	assert.NoError(t, err)
+	brewed, err := NewCoffeeshopUsecase(manager, m, entity.NewOrderMetrics()).
+		ExecuteBrew(t.Context(), orders, 1, entity.SchedulingFIFO)
+	assert.NoError(t, err)
+
+	// the steps wait and take as long on both, the worker IDs aside
+	assert.Len(t, brewed, len(simulated))
+	for i, want := range simulated {
+		got := brewed[i]
+		assert.Equal(t, want.OrderID, got.OrderID)
+		assert.Equal(t, want.Status, got.Status)
+		assert.Len(t, got.Steps, len(want.Steps))
+		for j := range min(len(want.Steps), len(got.Steps)) {
+			w, g := want.Steps[j], got.Steps[j]
+			assert.InDelta(t, w.AcquiredAtMs-w.QueuedAtMs, g.AcquiredAtMs-g.QueuedAtMs, 10, "order %d step %d wait", want.OrderID, j)
+			assert.InDelta(t, w.ReleasedAtMs-w.AcquiredAtMs, g.ReleasedAtMs-g.AcquiredAtMs, 10, "order %d step %d", want.OrderID, j)
+		}
+	}
+}
+
+func TestExecuteBrewDeadlines(t *testing.T) {
+	manager := newTestManager(t)
+
+	metrics := entity.NewOrderMetrics()
+
+	usecase := NewCoffeeshopUsecase(manager, newTestMenu(t), metrics,
+		WithClock(clock.NewVirtual(time.UnixMilli(0))),
+		WithSimulation(),
+	)
+
+	orders := []entity.Order{
+		{ID: 1, Drink: entity.DrinkFrappe},
+		{ID: 2, Drink: entity.DrinkLatte, DueAtMs: 100},
+		{ID: 3, Drink: entity.DrinkEspresso, DueAtMs: 10},
+	}
+	results, err := usecase.ExecuteBrew(t.Context(), orders, 1, entity.SchedulingDeadline)
+	assert.NoError(t, err)
+
+	// the single barista brews the espresso (0 to 13ms), then the latte (13 to
+	// 28ms) and the frappe without a due time last
+	tests := map[int64]struct {
+		completedAtMs int64
+		latenessMs    int64
+		metDeadline   bool
+	}{
+		1: {completedAtMs: 45},
+		2: {completedAtMs: 28, latenessMs: -72, metDeadline: true},
+		3: {completedAtMs: 13, latenessMs: 3},
+	}
+	assert.Len(t, results, len(tests))
+	for _, res := range results {
+		tt := tests[res.OrderID]
+		assert.Equal(t, entity.OrderStatusCompleted, res.Status, "order %d", res.OrderID)
+		assert.Equal(t, tt.completedAtMs, res.CompletedAtMs(), "order %d", res.OrderID)
+		assert.Equal(t, tt.latenessMs, res.LatenessMs, "order %d", res.OrderID)
+		assert.Equal(t, tt.metDeadline, res.MetDeadline(), "order %d", res.OrderID)
+	}
+
+	snapshot := metrics.Snapshot()
+	assert.Equal(t, int64(2), snapshot.DueOrders)
+	assert.Equal(t, int64(1), snapshot.OnTimeOrders)
+	assert.Equal(t, 50.0, snapshot.OnTimePercent())
+}
+
+func TestExecuteBrewReportsFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		orders     []entity.Order
+		cancel     bool
+		faults     map[entity.EquipmentType]entity.FaultModel
+		wantStatus map[int64]entity.OrderStatus
+	}{
+		{
+			name:   "completed",
+			orders: []entity.Order{{ID: 1, Drink: entity.DrinkEspresso}},
+			wantStatus: map[int64]entity.OrderStatus{
+				1: entity.OrderStatusCompleted,
+			},
+		},
+		{
+			name:   "context cancelled",
+			orders: []entity.Order{{ID: 1, Drink: entity.DrinkEspresso}, {ID: 2, Drink: entity.DrinkLatte}},
+			cancel: true,
+			wantStatus: map[int64]entity.OrderStatus{
+				1: entity.OrderStatusCancelled,
+				2: entity.OrderStatusCancelled,
+			},
+		},
+		{
+			name:   "equipment broken down",
+			orders: []entity.Order{{ID: 1, Drink: entity.DrinkFrappe}, {ID: 2, Drink: entity.DrinkFrappe}},
+			// the only blender breaks down after the first frappe
+			faults: map[entity.EquipmentType]entity.FaultModel{
+				entity.EquipBlender: {MTBF: time.Nanosecond, RepairTime: time.Minute, OnBreakdown: entity.BreakdownFail},
+			},
+			wantStatus: map[int64]entity.OrderStatus{
+				1: entity.OrderStatusCompleted,
+				2: entity.OrderStatusFailed,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			manager := newTestManager(t)
+			for k, v := range test.faults {
+				assert.NoError(t, manager.SetFaults(k, v))
+			}
+
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+			if test.cancel {
+				cancel()
+			}
+
+			usecase := NewCoffeeshopUsecase(manager, newTestMenu(t), entity.NewOrderMetrics())
+			results, err := usecase.ExecuteBrew(ctx, test.orders, 1, "")
+			assert.NoError(t, err)
+
+			assert.Len(t, results, len(test.orders))
+			for _, res := range results {
+				assert.Equal(t, test.wantStatus[res.OrderID], res.Status, "order %d", res.OrderID)
+				if res.Status == entity.OrderStatusCompleted {
+					assert.Empty(t, res.Error)
+				} else {
+					assert.NotEmpty(t, res.Error)
+					assert.Empty(t, res.Steps)
+				}
+			}
+
+		})
+	}
+}
+
+func TestExecuteBrewOutOfStock(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{
+			name: "worker pools",
+		},
+		{
+			name: "simulation",
+			opts: []Option{WithClock(clock.NewVirtual(time.UnixMilli(0))), WithSimulation()},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			manager := newTestManager(t)
+
+			// milk for a single latte
+			m, err := menu.Parse([]byte(`
+inventory:
+  beans: {stock: 100}
+  milk: {stock: 300, low_stock: 200}
+drinks:
+  - name: latte
+    ingredients: {beans: 18, milk: 200}
+    steps:
+      - equipment: grinder
+        duration: 5ms
+      - equipment: milk_steamer
+        duration: 5ms
+`))
+			assert.NoError(t, err)
+
+			usecase := NewCoffeeshopUsecase(manager, m, entity.NewOrderMetrics(), test.opts...)
+			orders := []entity.Order{{ID: 1, Drink: entity.DrinkLatte}, {ID: 2, Drink: entity.DrinkLatte}}
+			results, err := usecase.ExecuteBrew(t.Context(), orders, 1, entity.SchedulingFIFO)
+			assert.NoError(t, err)
+
+			assert.Len(t, results, 2)
+			for _, res := range results {
+				if res.OrderID == 1 {
+					assert.Equal(t, entity.OrderStatusCompleted, res.Status)
+					continue
+				}
+				assert.Equal(t, entity.OrderStatusFailed, res.Status)
+				assert.Equal(t, "out of stock: milk (needs 200, 100 left)", res.Error)
+			}
+			assert.Equal(t, []entity.IngredientLevel{
+				{Ingredient: entity.IngredientBeans, Quantity: 82},
+				{Ingredient: entity.IngredientMilk, Quantity: 100, LowStock: 200},
+			}, usecase.GetInventory())
+
+			// restocking lets the next latte through
+			level, err := usecase.Restock(entity.IngredientMilk, 100)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(200), level.Quantity)
+
+			results, err = usecase.ExecuteBrew(t.Context(), []entity.Order{{ID: 3, Drink: entity.DrinkLatte}}, 1, "")
+			assert.NoError(t, err)
+			assert.Equal(t, entity.OrderStatusCompleted, results[0].Status)
+		})
+	}
+}
+
+func TestExecuteBrewValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		baristas   int
+		orders     []entity.Order
+		wantFields []string
+	}{
+		{
+			name:       "no barista",
+			baristas:   0,
+			orders:     []entity.Order{{ID: 1, Drink: entity.DrinkEspresso}},
+			wantFields: []string{"baristas"},
+		},
+		{
+			name:     "unknown drinks and duplicate ids",
+			baristas: 1,
+			orders: []entity.Order{
+				{ID: 1, Drink: entity.DrinkEspresso},
+				{ID: 2, Drink: entity.DrinkUnspecified},
+				{ID: 1, Drink: entity.DrinkLatte},
+				{ID: 0, Drink: entity.DrinkType("mocha")},
+			},
+			wantFields: []string{"orders[1].drink", "orders[2].id", "orders[3].id", "orders[3].drink"},
+		},
+		{
+			name:     "modifiers",
+			baristas: 1,
+			orders: []entity.Order{
+				{ID: 1, Drink: entity.DrinkLatte, Modifiers: entity.Modifiers{Size: entity.SizeLarge, ExtraShots: 1, Milk: entity.MilkOat, Iced: true}},
+				{ID: 2, Drink: entity.DrinkMatcha, Modifiers: entity.Modifiers{ExtraShots: 1}},
+				{ID: 3, Drink: entity.DrinkEspresso, Modifiers: entity.Modifiers{Size: "venti", ExtraShots: 4}},
+			},
+			wantFields: []string{"orders[2].modifiers.size", "orders[2].modifiers.extra_shots", "orders[0].modifiers", "orders[1].modifiers"},
+		},
+		{
+			name:     "due times and priorities",
+			baristas: 1,
+			orders: []entity.Order{
+				{ID: 1, Drink: entity.DrinkLatte, DueAtMs: 1000, Priority: entity.PriorityVIP},
+				{ID: 2, Drink: entity.DrinkLatte, DueAtMs: -1, Priority: "royal"},
+			},
+			wantFields: []string{"orders[1].due_at_ms", "orders[1].priority"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metrics := entity.NewOrderMetrics()
+			usecase := NewCoffeeshopUsecase(worker.NewEquipPoolManager(0, clock.New()), newTestMenu(t), metrics)
+
+			results, err := usecase.ExecuteBrew(t.Context(), test.orders, test.baristas, "")
+			assert.Nil(t, results)
+
+			var verr *apperrors.ValidationError
+			if assert.ErrorAs(t, err, &verr) {
+				fields := make([]string, len(verr.Violations))
+				for i, v := range verr.Violations {
+					fields[i] = v.Field
+				}
+				assert.Equal(t, test.wantFields, fields)
+			}
+
+			_, totalOrders, _ := usecase.GetStats()
+			assert.Equal(t, int64(0), totalOrders)
+		})
+	}
+}
+
+func TestProcessOrderUnknownRecipe(t *testing.T) {
+	usecase := NewCoffeeshopUsecase(worker.NewEquipPoolManager(0, clock.New()), newTestMenu(t), entity.NewOrderMetrics())
+
+	_, err := usecase.processOrder(t.Context(), newTestMenu(t), entity.Order{ID: 1, Drink: entity.DrinkUnspecified}, nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrUnknownRecipe)
+	assert.Equal(t, entity.OrderStatusFailed, failedResult(entity.Order{ID: 1}, err).Status)
+}
+
+func TestApplyMenu(t *testing.T) {
+	manager := newTestManager(t)
+
+	usecase := NewCoffeeshopUsecase(manager, newTestMenu(t), entity.NewOrderMetrics(),
+		WithClock(clock.NewVirtual(time.UnixMilli(0))),
+		WithSimulation(),
+	)
+
+	// espresso gets faster, with a second grinder
+	faster, err := menu.Parse([]byte(`
+equipment:
+  grinder: 2
+drinks:
+  - name: espresso
+    steps:
+      - equipment: grinder
+        duration: 2ms
+      - equipment: espresso_machine
+        duration: 4ms
+`))
+	assert.NoError(t, err)
+	assert.NoError(t, usecase.ApplyMenu(faster))
+	assert.Equal(t, uint8(2), manager.Workers()[entity.EquipGrinder])
+
+	results, err := usecase.ExecuteBrew(t.Context(), []entity.Order{{ID: 1, Drink: entity.DrinkEspresso}}, 1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), results[0].Steps[1].EndTimeMs)
+
+	// a grinder added live is kept by a menu that leaves the grinders as they were
+	_, err = usecase.ResizeEquipment(entity.EquipGrinder, 3)
+	assert.NoError(t, err)
+	assert.NoError(t, usecase.ApplyMenu(faster))
+	assert.Equal(t, uint8(3), manager.Workers()[entity.EquipGrinder])
+
+	// latte is gone from the menu
+	_, err = usecase.ExecuteBrew(t.Context(), []entity.Order{{ID: 2, Drink: entity.DrinkLatte}}, 1, "")
+	var verr *apperrors.ValidationError
+	assert.ErrorAs(t, err, &verr)
+
+	// a menu needing a missing equipment is rejected and the previous one kept
+	broken, err := menu.New(map[entity.DrinkType][]entity.RecipeStep{
+		entity.DrinkEspresso: {{Equipment: entity.EquipmentType(42), Duration: time.Millisecond}},
+	})
+	assert.NoError(t, err)
+	assert.Error(t, usecase.ApplyMenu(broken))
+
+	results, err = usecase.ExecuteBrew(t.Context(), []entity.Order{{ID: 3, Drink: entity.DrinkEspresso}}, 1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusCompleted, results[0].Status)
+}
+
+func TestApplyMenuRejected(t *testing.T) {
+	// no whisk to set the faults of
+	manager := worker.NewEquipPoolManager(2, clock.New())
+	manager.Register(entity.EquipGrinder, 1)
+	manager.Register(entity.EquipEspressoMachine, 1)
+
+	m, err := menu.New(map[entity.DrinkType][]entity.RecipeStep{
+		entity.DrinkEspresso: {{Equipment: entity.EquipGrinder, Duration: time.Millisecond}},
+	})
+	assert.NoError(t, err)
+	usecase := NewCoffeeshopUsecase(manager, m, entity.NewOrderMetrics())
+
+	tests := []struct {
+		name string
+		menu string
+	}{
+		{
+			name: "equipment without a pool",
+			menu: `
+equipment:
+  grinder: 2
+  whisk:
+    clean_every: 2
+    cleaning: 1ms
+drinks:
+  - name: espresso
+    steps:
+      - equipment: grinder
+        duration: 1ms
+`,
+		},
+		{
+			name: "equipment without workers",
+			menu: `
+equipment:
+  grinder: 2
+  espresso_machine:
+    clean_every: 2
+    cleaning: 1ms
+drinks:
+  - name: latte
+    steps:
+      - equipment: milk_steamer
+        duration: 1ms
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, err := menu.Parse([]byte(tt.menu))
+			assert.NoError(t, err)
+
+			assert.Error(t, usecase.ApplyMenu(next))
+			assert.Equal(t, uint8(1), manager.Workers()[entity.EquipGrinder])
+			assert.Equal(t, entity.FaultModel{}, manager.Faults()[entity.EquipEspressoMachine])
+			_, err = usecase.ExecuteBrew(t.Context(), []entity.Order{{ID: 1, Drink: entity.DrinkLatte}}, 1, "")
+			var verr *apperrors.ValidationError
+			assert.ErrorAs(t, err, &verr)
+		})
+	}
+}
+
+func TestStreamBrew(t *testing.T) {
+	manager := newTestManager(t)
+
+	type event struct {
+		typ   entity.BrewEventType
+		state entity.OrderState // for state events
+		step  int               // of the brewing state
+	}
+	wantEvents := []event{
+		{typ: entity.BrewEventOrderState, state: entity.OrderStateQueued},
+		{typ: entity.BrewEventStepStarted},
+		{typ: entity.BrewEventOrderState, state: entity.OrderStateBrewing, step: 0},
+		{typ: entity.BrewEventStepFinished},
+		{typ: entity.BrewEventStepStarted},
+		{typ: entity.BrewEventOrderState, state: entity.OrderStateBrewing, step: 1},
+		{typ: entity.BrewEventStepFinished},
+		{typ: entity.BrewEventOrderCompleted},
+		{typ: entity.BrewEventOrderState, state: entity.OrderStateReady},
+		// the caller takes the drink along with the results
+		{typ: entity.BrewEventOrderState, state: entity.OrderStatePickedUp},
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{
+			name: "worker pools",
+		},
+		{
+			name: "simulation",
+			opts: []Option{WithClock(clock.NewVirtual(time.UnixMilli(0))), WithSimulation()},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metrics := entity.NewOrderMetrics()
+			usecase := NewCoffeeshopUsecase(manager, newTestMenu(t), metrics, test.opts...)
+
+			var events []entity.BrewEvent
+			results, err := usecase.StreamBrew(t.Context(), []entity.Order{{ID: 1, Drink: entity.DrinkEspresso}}, 1, "", func(ev entity.BrewEvent) {
+				events = append(events, ev)
+			})
+
+			assert.NoError(t, err)
+			assert.Len(t, results, 1)
+			var got []event
+			lastAtMs := int64(0)
+			for _, ev := range events {
+				assert.Equal(t, int64(1), ev.OrderID)
+				got = append(got, event{typ: ev.Type, state: ev.Transition.To, step: ev.Transition.Step})
+				if ev.Type == entity.BrewEventOrderState {
+					assert.GreaterOrEqual(t, ev.Transition.AtMs, lastAtMs)
+					lastAtMs = ev.Transition.AtMs
+				}
+			}
+			assert.Equal(t, wantEvents, got)
+			assert.Equal(t, results[0], events[7].Result)
+			assert.Equal(t, results[0].Steps[1], events[6].Step)
+			assert.Equal(t, map[entity.OrderState]int64{
+				entity.OrderStateReceived: 1,
+				entity.OrderStateQueued:   1,
+				entity.OrderStateBrewing:  2,
+				entity.OrderStateReady:    1,
+				entity.OrderStatePickedUp: 1,
+			}, metrics.Snapshot().Transitions)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package coffeeshop
+
+import (
+	"context"
+	"fmt"
+	"gopher-cafe/internal/menu"
+	"gopher-cafe/internal/worker"
+	"slices"
+	"time"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+)
+
+// refuseLate splits the orders, in the sequence the baristas pick them up in,
+// into the ones that may finish before the deadline of ctx and the results of
+// the ones that cannot. Each order is estimated to take its recipe duration
+// once its barista is free and the steps already queued on its equipment are
+// through. The orders refused do not hold up the next ones.
+//
+// The estimate is a lower bound: the orders of the request do not wait for
+// each other's steps on the same equipment, so an order it admits may still
+// miss the deadline when they contend for it.
+func (u *CoffeeshopUsecase) refuseLate(ctx context.Context, m *menu.Menu, orders []entity.Order, baristas int) ([]entity.Order, []entity.OrderResult) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return orders, nil
+	}
+	remaining := deadline.Sub(u.clock.Now())
+
+	stats := u.equipPoolManager.Stats()
+
+	var (
+		admitted []entity.Order
+		refused  []entity.OrderResult
+	)
+	free := make([]time.Duration, baristas) // from now, when each barista is free
+	for _, order := range orders {
+		recipe, _, _ := m.Expand(order)
+
+		b := slices.Index(free, slices.Min(free))
+		finish := max(free[b], backlog(recipe, stats)) + entity.RecipeDuration(recipe)
+		if finish > remaining {
+			err := fmt.Errorf("%w: needs about %s, %s left", apperrors.ErrDeadlineUnreachable, finish, remaining.Round(time.Millisecond))
+			refused = append(refused, failedResult(order, err))
+			continue
+		}
+
+		free[b] = finish
+		admitted = append(admitted, order)
+	}
+
+	return admitted, refused
+}
+
+// backlog is how long the steps queued on the equipment of a recipe hold it
+// up, taking each of them to last about as long as the step of the recipe.
+func backlog(recipe []entity.RecipeStep, stats map[entity.EquipmentType]worker.PoolStats) time.Duration {
+	var wait time.Duration
+	for _, step := range recipe {
+		s, ok := stats[step.Equipment]
+		if !ok || s.Workers == 0 {
+			continue
+		}
+		rounds := (s.QueueDepth + s.Workers - 1) / s.Workers
+		wait = max(wait, time.Duration(rounds)*step.Duration)
+	}
+
+	return wait
+}
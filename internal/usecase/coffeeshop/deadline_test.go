@@ -0,0 +1,92 @@
+package coffeeshop
+
+import (
+	"context"
+	"gopher-cafe/internal/clock"
+	"gopher-cafe/internal/worker"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+)
+
+func TestRefuseLate(t *testing.T) {
+	espressos := []entity.Order{
+		{ID: 1, Drink: entity.DrinkEspresso},
+		{ID: 2, Drink: entity.DrinkEspresso},
+		{ID: 3, Drink: entity.DrinkEspresso},
+	}
+
+	tests := []struct {
+		name        string
+		deadlineMs  int64 // from now, none when zero
+		baristas    int
+		queued      int // steps waiting for the grinder
+		wantRefused []int64
+	}{
+		{
+			name:     "no deadline",
+			baristas: 1,
+		},
+		{
+			// an espresso takes 13ms
+			name:        "one barista",
+			deadlineMs:  30,
+			baristas:    1,
+			wantRefused: []int64{3},
+		},
+		{
+			name:       "baristas side by side",
+			deadlineMs: 30,
+			baristas:   2,
+		},
+		{
+			name:        "held up by the equipment",
+			deadlineMs:  30,
+			baristas:    3,
+			queued:      4, // 20ms of grinding ahead
+			wantRefused: []int64{1, 2, 3},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clk := clock.NewVirtual(time.UnixMilli(0))
+			// not started, so the steps sent to the grinder stay queued
+			manager := worker.NewEquipPoolManager(1, clk)
+			manager.Register(entity.EquipGrinder, 1)
+			grinder, err := manager.GetWorkerPool(entity.EquipGrinder)
+			assert.NoError(t, err)
+
+			queueCtx, stopQueue := context.WithCancel(t.Context())
+			defer stopQueue()
+			for i := range test.queued {
+				go grinder.Submit(queueCtx, worker.Job{OrderID: int64(100 + i)})
+			}
+			assert.Eventually(t, func() bool {
+				return grinder.Stats().QueueDepth == test.queued
+			}, time.Second, time.Millisecond)
+
+			ctx := t.Context()
+			if test.deadlineMs != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, time.UnixMilli(test.deadlineMs))
+				defer cancel()
+			}
+
+			usecase := NewCoffeeshopUsecase(manager, newTestMenu(t), entity.NewOrderMetrics(), WithClock(clk))
+			admitted, refused := usecase.refuseLate(ctx, newTestMenu(t), espressos, test.baristas)
+
+			assert.Len(t, admitted, len(espressos)-len(test.wantRefused))
+			var gotRefused []int64
+			for _, res := range refused {
+				gotRefused = append(gotRefused, res.OrderID)
+				assert.Equal(t, entity.OrderStatusFailed, res.Status)
+				assert.Contains(t, res.Error, apperrors.ErrDeadlineUnreachable.Error())
+			}
+			assert.Equal(t, test.wantRefused, gotRefused)
+		})
+	}
+}
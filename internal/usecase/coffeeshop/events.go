@@ -0,0 +1,171 @@
+package coffeeshop
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+)
+
+// eventSink forwards brew events to the caller of StreamBrew one at a time,
+// since they are emitted from many barista goroutines, and drives the
+// lifecycle of the orders with them. Every transition is recorded in the
+// metrics and forwarded as a state event right after the event behind it.
+// A nil onEvent drops the events, and a nil sink drops them along with the
+// transitions.
+type eventSink struct {
+	ctx        context.Context // of the request, to tag the logs with
+	onEvent    func(entity.BrewEvent)
+	lifecycles map[int64]*entity.Lifecycle
+	metrics    *entity.OrderMetrics
+	mu         sync.Mutex
+}
+
+// newEventSink starts the lifecycle of the orders, received at receivedAtMs.
+func newEventSink(ctx context.Context, orders []entity.Order, receivedAtMs int64, metrics *entity.OrderMetrics, onEvent func(entity.BrewEvent)) *eventSink {
+	s := &eventSink{
+		ctx:        ctx,
+		onEvent:    onEvent,
+		lifecycles: make(map[int64]*entity.Lifecycle, len(orders)),
+		metrics:    metrics,
+	}
+	for _, order := range orders {
+		l := entity.NewLifecycle(receivedAtMs)
+		s.lifecycles[order.ID] = &l
+		metrics.RecordTransition(l.Transitions[0])
+	}
+
+	return s
+}
+
+func (s *eventSink) emit(ev entity.BrewEvent) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.send(ev)
+
+	switch ev.Type {
+	case entity.BrewEventStepStarted:
+		s.transition(ev.OrderID, entity.OrderStateBrewing, ev.StepIndex, ev.TimeMs)
+	case entity.BrewEventOrderCompleted:
+		s.transition(ev.OrderID, entity.OrderStateReady, 0, ev.TimeMs)
+	case entity.BrewEventOrderFailed:
+		to := entity.OrderStateFailed
+		if ev.Result.Status == entity.OrderStatusCancelled {
+			to = entity.OrderStateCancelled
+		}
+		s.transition(ev.OrderID, to, 0, ev.TimeMs)
+	}
+}
+
+// queue moves an order handed to the baristas to queued.
+func (s *eventSink) queue(orderID, atMs int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transition(orderID, entity.OrderStateQueued, 0, atMs)
+}
+
+// pickUp moves the ready orders to picked up, at atMs or at the time they got
+// ready if later, as simulated orders get ready ahead of the clock.
+func (s *eventSink) pickUp(atMs int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range slices.Sorted(maps.Keys(s.lifecycles)) {
+		l := s.lifecycles[id]
+		if l.State() != entity.OrderStateReady {
+			continue
+		}
+		readyAt := l.Transitions[len(l.Transitions)-1].AtMs
+		s.transition(id, entity.OrderStatePickedUp, 0, max(atMs, readyAt))
+	}
+}
+
+// transition moves an order along its lifecycle. s.mu must be held.
+func (s *eventSink) transition(orderID int64, to entity.OrderState, step int, atMs int64) {
+	l, ok := s.lifecycles[orderID]
+	if !ok {
+		return
+	}
+
+	tr, err := l.Transition(to, step, atMs)
+	if err != nil {
+		errorf(s.ctx, "Order %d: %s", orderID, err)
+		return
+	}
+	s.metrics.RecordTransition(tr)
+
+	s.send(entity.BrewEvent{
+		Type:       entity.BrewEventOrderState,
+		OrderID:    orderID,
+		TimeMs:     atMs,
+		Transition: tr,
+	})
+}
+
+// send forwards an event to the caller. s.mu must be held.
+func (s *eventSink) send(ev entity.BrewEvent) {
+	if s.onEvent != nil {
+		s.onEvent(ev)
+	}
+}
+
+// eventsFromResults rebuilds the events of a brew started at startMs from its
+// results, in time order, for brews that were not observed as they ran. Orders
+// that did not complete fail at the start of the brew.
+func eventsFromResults(startMs int64, results []entity.OrderResult) []entity.BrewEvent {
+	var events []entity.BrewEvent
+	for _, res := range results {
+		if res.Status != entity.OrderStatusCompleted {
+			events = append(events, entity.BrewEvent{
+				Type:    entity.BrewEventOrderFailed,
+				OrderID: res.OrderID,
+				TimeMs:  startMs,
+				Result:  res,
+			})
+			continue
+		}
+
+		completedAt := startMs
+		for i, step := range res.Steps {
+			events = append(events,
+				entity.BrewEvent{
+					Type:      entity.BrewEventStepStarted,
+					OrderID:   res.OrderID,
+					TimeMs:    step.StartTimeMs,
+					StepIndex: i,
+					Step:      entity.StepExecution{Equipment: step.Equipment, StartTimeMs: step.StartTimeMs},
+				},
+				entity.BrewEvent{
+					Type:      entity.BrewEventStepFinished,
+					OrderID:   res.OrderID,
+					TimeMs:    step.EndTimeMs,
+					StepIndex: i,
+					Step:      step,
+				},
+			)
+			completedAt = max(completedAt, step.EndTimeMs)
+		}
+
+		events = append(events, entity.BrewEvent{
+			Type:    entity.BrewEventOrderCompleted,
+			OrderID: res.OrderID,
+			TimeMs:  completedAt,
+			Result:  res,
+		})
+	}
+
+	slices.SortStableFunc(events, func(a, b entity.BrewEvent) int {
+		return cmp.Compare(a.TimeMs, b.TimeMs)
+	})
+
+	return events
+}
@@ -0,0 +1,34 @@
+package coffeeshop
+
+import (
+	"context"
+	"gopher-cafe/internal/requestid"
+
+	"github.com/ajaibid/coin-common-golang/logger"
+)
+
+// debugf logs like logger.Debugf, tagged with the ID of the request of ctx.
+func debugf(ctx context.Context, format string, args ...any) {
+	logger.Debugf(withRequestID(ctx, format), args...)
+}
+
+// infof logs like logger.Infof, tagged with the ID of the request of ctx.
+func infof(ctx context.Context, format string, args ...any) {
+	logger.Infof(withRequestID(ctx, format), args...)
+}
+
+// errorf logs like logger.Errorf, tagged with the ID of the request of ctx.
+func errorf(ctx context.Context, format string, args ...any) {
+	logger.Errorf(withRequestID(ctx, format), args...)
+}
+
+// withRequestID prefixes format with the request ID of ctx, if any. Request
+// IDs are only made of characters that are safe in a format.
+func withRequestID(ctx context.Context, format string) string {
+	id := requestid.FromContext(ctx)
+	if id == "" {
+		return format
+	}
+
+	return "request_id=" + id + " " + format
+}
@@ -0,0 +1,106 @@
+package coffeeshop
+
+import (
+	"cmp"
+	"gopher-cafe/internal/menu"
+	"slices"
+	"time"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+)
+
+// Scheduler decides the sequence in which baristas pick up the orders of a
+// single brew request, with the recipes of the orders on m.
+type Scheduler interface {
+	Schedule(orders []entity.Order, m *menu.Menu) []entity.Order
+}
+
+var schedulers = map[entity.SchedulingStrategy]Scheduler{
+	entity.SchedulingFIFO:          fifoScheduler{},
+	entity.SchedulingShortestFirst: processingTimeScheduler{},
+	entity.SchedulingLongestFirst:  processingTimeScheduler{longestFirst: true},
+	entity.SchedulingRoundRobin:    roundRobinScheduler{},
+	entity.SchedulingDeadline:      deadlineScheduler{},
+}
+
+// fifoScheduler keeps the orders as they were sent.
+type fifoScheduler struct{}
+
+func (fifoScheduler) Schedule(orders []entity.Order, _ *menu.Menu) []entity.Order {
+	return slices.Clone(orders)
+}
+
+// processingTimeScheduler sorts the orders by the duration of their recipe
+// with its modifiers applied, shortest first unless longestFirst is set.
+// Orders with the same duration keep their original sequence.
+type processingTimeScheduler struct {
+	longestFirst bool
+}
+
+func (s processingTimeScheduler) Schedule(orders []entity.Order, m *menu.Menu) []entity.Order {
+	// order IDs are unique within a request
+	durations := make(map[int64]time.Duration, len(orders))
+	for _, order := range orders {
+		recipe, _, _ := m.Expand(order)
+		durations[order.ID] = entity.RecipeDuration(recipe)
+	}
+
+	scheduled := slices.Clone(orders)
+	slices.SortStableFunc(scheduled, func(a, b entity.Order) int {
+		if s.longestFirst {
+			a, b = b, a
+		}
+		return cmp.Compare(durations[a.ID], durations[b.ID])
+	})
+
+	return scheduled
+}
+
+// roundRobinScheduler interleaves the drinks, taking one order of each drink
+// in turn, in the sequence the drinks first appear in the request.
+type roundRobinScheduler struct{}
+
+func (roundRobinScheduler) Schedule(orders []entity.Order, _ *menu.Menu) []entity.Order {
+	var drinks []entity.DrinkType
+	byDrink := make(map[entity.DrinkType][]entity.Order)
+	for _, order := range orders {
+		if _, ok := byDrink[order.Drink]; !ok {
+			drinks = append(drinks, order.Drink)
+		}
+		byDrink[order.Drink] = append(byDrink[order.Drink], order)
+	}
+
+	scheduled := make([]entity.Order, 0, len(orders))
+	for len(scheduled) < len(orders) {
+		for _, drink := range drinks {
+			if queue := byDrink[drink]; len(queue) > 0 {
+				scheduled = append(scheduled, queue[0])
+				byDrink[drink] = queue[1:]
+			}
+		}
+	}
+
+	return scheduled
+}
+
+// deadlineScheduler sorts the orders by due time, earliest first. Orders
+// without a due time come after the others, in their original sequence.
+type deadlineScheduler struct{}
+
+func (deadlineScheduler) Schedule(orders []entity.Order, _ *menu.Menu) []entity.Order {
+	scheduled := slices.Clone(orders)
+	slices.SortStableFunc(scheduled, func(a, b entity.Order) int {
+		switch {
+		case a.DueAtMs == b.DueAtMs:
+			return 0
+		case a.DueAtMs == 0:
+			return 1
+		case b.DueAtMs == 0:
+			return -1
+		default:
+			return cmp.Compare(a.DueAtMs, b.DueAtMs)
+		}
+	})
+
+	return scheduled
+}
@@ -0,0 +1,63 @@
+package coffeeshop
+
+import (
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	orders := []entity.Order{
+		{ID: 1, Drink: entity.DrinkLatte},                  // 15ms
+		{ID: 2, Drink: entity.DrinkEspresso, DueAtMs: 200}, // 13ms
+		// a small latte takes 11.25ms
+		{ID: 3, Drink: entity.DrinkLatte, Modifiers: entity.Modifiers{Size: entity.SizeSmall}},
+		{ID: 4, Drink: entity.DrinkFrappe, DueAtMs: 100},   // 17ms
+		{ID: 5, Drink: entity.DrinkEspresso, DueAtMs: 100}, // 13ms
+		{ID: 6, Drink: entity.DrinkMatcha},                 // 18ms
+	}
+
+	tests := []struct {
+		name     string
+		strategy entity.SchedulingStrategy
+		wantIDs  []int64
+	}{
+		{
+			name:     "fifo",
+			strategy: entity.SchedulingFIFO,
+			wantIDs:  []int64{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:     "shortest processing time first",
+			strategy: entity.SchedulingShortestFirst,
+			wantIDs:  []int64{3, 2, 5, 1, 4, 6},
+		},
+		{
+			name:     "longest processing time first",
+			strategy: entity.SchedulingLongestFirst,
+			wantIDs:  []int64{6, 4, 1, 2, 5, 3},
+		},
+		{
+			name:     "earliest deadline first",
+			strategy: entity.SchedulingDeadline,
+			wantIDs:  []int64{4, 5, 2, 1, 3, 6},
+		},
+		{
+			name:     "round robin by drink",
+			strategy: entity.SchedulingRoundRobin,
+			wantIDs:  []int64{1, 2, 4, 6, 3, 5},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scheduled := schedulers[test.strategy].Schedule(orders, newTestMenu(t))
+
+			ids := make([]int64, len(scheduled))
+			for i, order := range scheduled {
+				ids[i] = order.ID
+			}
+			assert.Equal(t, test.wantIDs, ids)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package coffeeshop
+
+import (
+	"context"
+	"gopher-cafe/internal/admission"
+	"time"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+
+	"github.com/ajaibid/coin-common-golang/logger"
+)
+
+// ticketRetention is how long a done ticket can still be looked up.
+const ticketRetention = 15 * time.Minute
+
+// SubmitOrders checks the orders like ExecuteBrew and brews them in the
+// background, returning the ticket to follow them with. ctx only covers the
+// submission, the brew goes on after it is done until the ticket is
+// cancelled or DrainTickets gives up on it. The orders that get ready wait for
+// PickUpOrder. The brew keeps the admission slot of the request until it is
+// done.
+func (u *CoffeeshopUsecase) SubmitOrders(ctx context.Context, orders []entity.Order, baristas int, strategy entity.SchedulingStrategy) (entity.Ticket, error) {
+	m := u.menu.Load()
+	if err := validateBrew(m, orders, baristas); err != nil {
+		return entity.Ticket{}, err
+	}
+
+	progress := make([]entity.OrderProgress, len(orders))
+	for i, order := range orders {
+		recipe, _, _ := m.Expand(order)
+		progress[i] = entity.OrderProgress{Order: order, StepsTotal: len(recipe)}
+	}
+
+	brewCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	ticket := u.tickets.Create(progress, cancel)
+	infof(ctx, "Ticket %s submitted with %d orders", ticket.ID, len(orders))
+
+	release := admission.Hold(ctx)
+	u.brewing.Add(1)
+	go func() {
+		defer u.brewing.Done()
+		defer release()
+		defer cancel()
+		defer context.AfterFunc(u.ticketsStopped, cancel)()
+
+		_, _, err := u.brew(brewCtx, orders, baristas, strategy, func(ev entity.BrewEvent) {
+			if err := u.tickets.Apply(ticket.ID, ev); err != nil {
+				errorf(brewCtx, "Updating ticket %s failed: %s", ticket.ID, err)
+			}
+		})
+		if err != nil {
+			errorf(brewCtx, "Brewing ticket %s failed: %s", ticket.ID, err)
+			for _, tr := range u.tickets.Fail(ticket.ID, err) {
+				u.metrics.RecordTransition(tr)
+			}
+			return
+		}
+		debugf(brewCtx, "Ticket %s done", ticket.ID)
+	}()
+
+	return ticket, nil
+}
+
+// DrainTickets waits up to timeout for the tickets brewing in the background
+// to be done, cancelling them past it. It is meant for the shutdown, before the
+// equipment is stopped.
+func (u *CoffeeshopUsecase) DrainTickets(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		u.brewing.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		logger.Errorf("Tickets brewing did not finish within %s, cancelling them", timeout)
+		u.stopTickets()
+		<-done
+	}
+}
+
+// GetOrderStatus returns how far the orders of a ticket got.
+func (u *CoffeeshopUsecase) GetOrderStatus(ticketID string) (entity.Ticket, error) {
+	return u.tickets.Get(ticketID)
+}
+
+// PickUpOrder hands over the ready orders of a ticket.
+func (u *CoffeeshopUsecase) PickUpOrder(ctx context.Context, ticketID string) (entity.Ticket, error) {
+	ticket, transitions, err := u.tickets.PickUp(ticketID)
+	if err != nil {
+		return entity.Ticket{}, err
+	}
+	for _, tr := range transitions {
+		u.metrics.RecordTransition(tr)
+	}
+	infof(ctx, "Ticket %s picked up %d orders", ticketID, len(transitions))
+
+	return ticket, nil
+}
+
+// CancelOrder stops brewing the orders of a ticket that are not brewed yet.
+func (u *CoffeeshopUsecase) CancelOrder(ctx context.Context, ticketID string) (entity.Ticket, error) {
+	ticket, err := u.tickets.Cancel(ticketID)
+	if err != nil {
+		return entity.Ticket{}, err
+	}
+	infof(ctx, "Ticket %s cancelled", ticketID)
+
+	return ticket, nil
+}
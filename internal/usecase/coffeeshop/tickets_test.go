@@ -0,0 +1,186 @@
+package coffeeshop
+
+import (
+	"gopher-cafe/internal/admission"
+	"gopher-cafe/internal/clock"
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+	"gopher-cafe/internal/worker"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmitOrders(t *testing.T) {
+	manager := newTestManager(t)
+
+	tests := []struct {
+		name   string
+		orders int
+		cancel bool
+		check  func(t *testing.T, ticket entity.Ticket)
+	}{
+		{
+			name:   "brewed in the background",
+			orders: 2,
+			check: func(t *testing.T, ticket entity.Ticket) {
+				for _, o := range ticket.Orders {
+					// ready until picked up
+					assert.Equal(t, entity.OrderStateReady, o.Lifecycle.State())
+					assert.Equal(t, entity.OrderStatusCompleted, o.Result.Status)
+					assert.Equal(t, 2, o.StepsTotal)
+					assert.Equal(t, o.StepsTotal, o.StepsDone)
+					assert.Empty(t, o.CurrentSteps)
+				}
+			},
+		},
+		{
+			name: "cancelled",
+			// the single barista is still on the first orders when the
+			// ticket is cancelled
+			orders: 10,
+			cancel: true,
+			check: func(t *testing.T, ticket entity.Ticket) {
+				last := ticket.Orders[len(ticket.Orders)-1]
+				assert.Equal(t, entity.OrderStateCancelled, last.Lifecycle.State())
+				assert.Equal(t, entity.OrderStatusCancelled, last.Result.Status)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			usecase := NewCoffeeshopUsecase(manager, newTestMenu(t), entity.NewOrderMetrics())
+
+			orders := make([]entity.Order, test.orders)
+			for i := range orders {
+				orders[i] = entity.Order{ID: int64(i + 1), Drink: entity.DrinkEspresso}
+			}
+
+			ticket, err := usecase.SubmitOrders(t.Context(), orders, 1, "")
+			assert.NoError(t, err)
+			assert.NotEmpty(t, ticket.ID)
+			assert.Len(t, ticket.Orders, test.orders)
+
+			if test.cancel {
+				_, err := usecase.CancelOrder(t.Context(), ticket.ID)
+				assert.NoError(t, err)
+			}
+
+			assert.Eventually(t, func() bool {
+				ticket, err = usecase.GetOrderStatus(ticket.ID)
+				return err == nil && ticket.Done()
+			}, time.Second, 5*time.Millisecond)
+			test.check(t, ticket)
+
+			ticket, err = usecase.PickUpOrder(t.Context(), ticket.ID)
+			assert.NoError(t, err)
+			for _, o := range ticket.Orders {
+				if o.Result.Status == entity.OrderStatusCompleted {
+					assert.Equal(t, entity.OrderStatePickedUp, o.Lifecycle.State())
+				}
+			}
+		})
+	}
+}
+
+func TestSubmitOrdersHoldsSlot(t *testing.T) {
+	usecase := NewCoffeeshopUsecase(newTestManager(t), newTestMenu(t), entity.NewOrderMetrics())
+	ctrl := admission.New(admission.Limits{Rate: 100, Burst: 100, MaxInFlight: 1}, clock.New())
+
+	submit := func(baristas int) (entity.Ticket, error) {
+		release, err := ctrl.Admit("a")
+		assert.NoError(t, err)
+		ctx, done := admission.NewContext(t.Context(), release)
+		defer done()
+
+		orders := make([]entity.Order, 5)
+		for i := range orders {
+			orders[i] = entity.Order{ID: int64(i + 1), Drink: entity.DrinkEspresso}
+		}
+		return usecase.SubmitOrders(ctx, orders, baristas, "")
+	}
+	admitted := func() bool {
+		release, err := ctrl.Admit("b")
+		if err != nil {
+			return false
+		}
+		release()
+		return true
+	}
+
+	// a rejected submission gives its slot back right away
+	_, err := submit(0)
+	assert.Error(t, err)
+	assert.True(t, admitted())
+
+	// the brew holds the slot past the submission
+	ticket, err := submit(1)
+	assert.NoError(t, err)
+	assert.False(t, admitted())
+
+	assert.Eventually(t, func() bool {
+		ticket, err = usecase.GetOrderStatus(ticket.ID)
+		return err == nil && ticket.Done()
+	}, time.Second, 5*time.Millisecond)
+	assert.Eventually(t, admitted, time.Second, 5*time.Millisecond)
+}
+
+func TestDrainTickets(t *testing.T) {
+	manager := newTestManager(t)
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		want    entity.OrderStatus
+	}{
+		{
+			name:    "brewed",
+			timeout: time.Second,
+			want:    entity.OrderStatusCompleted,
+		},
+		{
+			// the single barista is still on the first orders
+			name:    "cancelled past the timeout",
+			timeout: time.Millisecond,
+			want:    entity.OrderStatusCancelled,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			usecase := NewCoffeeshopUsecase(manager, newTestMenu(t), entity.NewOrderMetrics())
+
+			orders := make([]entity.Order, 10)
+			for i := range orders {
+				orders[i] = entity.Order{ID: int64(i + 1), Drink: entity.DrinkEspresso}
+			}
+			ticket, err := usecase.SubmitOrders(t.Context(), orders, 1, "")
+			assert.NoError(t, err)
+
+			usecase.DrainTickets(test.timeout)
+
+			ticket, err = usecase.GetOrderStatus(ticket.ID)
+			assert.NoError(t, err)
+			assert.True(t, ticket.Done())
+			last := ticket.Orders[len(ticket.Orders)-1]
+			assert.Equal(t, test.want, last.Result.Status)
+		})
+	}
+}
+
+func TestSubmitOrdersErrors(t *testing.T) {
+	usecase := NewCoffeeshopUsecase(worker.NewEquipPoolManager(0, clock.New()), newTestMenu(t), entity.NewOrderMetrics())
+
+	_, err := usecase.SubmitOrders(t.Context(), []entity.Order{{ID: 1, Drink: entity.DrinkEspresso}}, 0, "")
+	var verr *apperrors.ValidationError
+	assert.ErrorAs(t, err, &verr)
+
+	_, err = usecase.GetOrderStatus("unknown")
+	assert.ErrorIs(t, err, apperrors.ErrNoTicket)
+
+	_, err = usecase.CancelOrder(t.Context(), "unknown")
+	assert.ErrorIs(t, err, apperrors.ErrNoTicket)
+
+	_, err = usecase.PickUpOrder(t.Context(), "unknown")
+	assert.ErrorIs(t, err, apperrors.ErrNoTicket)
+}
@@ -1,10 +1,12 @@
 package worker
 
 import (
-	"errors"
+	"gopher-cafe/internal/clock"
 	"gopher-cafe/internal/entity/coffeeshop"
 	"sync"
 
+	apperrors "gopher-cafe/internal/errors"
+
 	"github.com/ajaibid/coin-common-golang/logger"
 )
 
@@ -19,21 +21,38 @@ var (
 )
 
 type EquipPoolManager struct {
-	pools map[coffeeshop.EquipmentType]*WorkerPool
-	mu    sync.RWMutex
+	pools   map[coffeeshop.EquipmentType]*WorkerPool
+	clock   clock.Clock
+	started bool
+	mu      sync.RWMutex
 }
 
-func NewEquipPoolManager(totalPool uint8) *EquipPoolManager {
+func NewEquipPoolManager(totalPool uint8, clk clock.Clock) *EquipPoolManager {
 	return &EquipPoolManager{
 		pools: make(map[coffeeshop.EquipmentType]*WorkerPool, totalPool),
+		clock: clk,
 	}
 }
 
+// Register adds the pool of an equipment, started right away once the pools
+// are. Registering an equipment again resizes its pool rather than replacing
+// it, so the jobs of the pool are not lost.
 func (e *EquipPoolManager) Register(equipType coffeeshop.EquipmentType, numOfWorkers uint8) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	e.pools[equipType] = NewWorkerPool(equipType.String(), numOfWorkers)
+	if pool, ok := e.pools[equipType]; ok {
+		if err := pool.Resize(numOfWorkers); err != nil {
+			logger.Errorf("Registering %s again failed: %s", equipType, err)
+		}
+		return
+	}
+
+	pool := NewWorkerPool(equipType.String(), numOfWorkers, e.clock)
+	if e.started {
+		pool.start()
+	}
+	e.pools[equipType] = pool
 }
 
 func (e *EquipPoolManager) GetWorkerPool(equipType coffeeshop.EquipmentType) (*WorkerPool, error) {
@@ -44,13 +63,75 @@ func (e *EquipPoolManager) GetWorkerPool(equipType coffeeshop.EquipmentType) (*W
 		return pool, nil
 	}
 
-	return nil, errors.New("no worker pool registered")
+	return nil, apperrors.ErrNoWorkerPool
+}
+
+// Resize grows or shrinks the pool of the equipment to n workers.
+func (e *EquipPoolManager) Resize(equipType coffeeshop.EquipmentType, n uint8) error {
+	pool, err := e.GetWorkerPool(equipType)
+	if err != nil {
+		return err
+	}
+
+	return pool.Resize(n)
+}
+
+// SetFaults sets how the workers of the equipment break down and get cleaned.
+func (e *EquipPoolManager) SetFaults(equipType coffeeshop.EquipmentType, faults coffeeshop.FaultModel) error {
+	pool, err := e.GetWorkerPool(equipType)
+	if err != nil {
+		return err
+	}
+
+	pool.SetFaults(faults)
+	return nil
+}
+
+// Workers returns the number of workers of every registered pool.
+func (e *EquipPoolManager) Workers() map[coffeeshop.EquipmentType]uint8 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	workers := make(map[coffeeshop.EquipmentType]uint8, len(e.pools))
+	for equipType, pool := range e.pools {
+		workers[equipType] = pool.Size()
+	}
+
+	return workers
+}
+
+// Faults returns how the workers of every registered pool break down and get
+// cleaned.
+func (e *EquipPoolManager) Faults() map[coffeeshop.EquipmentType]coffeeshop.FaultModel {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	faults := make(map[coffeeshop.EquipmentType]coffeeshop.FaultModel, len(e.pools))
+	for equipType, pool := range e.pools {
+		faults[equipType] = pool.getFaults()
+	}
+
+	return faults
+}
+
+// Stats returns the current stats of every registered pool.
+func (e *EquipPoolManager) Stats() map[coffeeshop.EquipmentType]PoolStats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	stats := make(map[coffeeshop.EquipmentType]PoolStats, len(e.pools))
+	for equipType, pool := range e.pools {
+		stats[equipType] = pool.Stats()
+	}
+
+	return stats
 }
 
 func (e *EquipPoolManager) StartAll() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	e.started = true
 	for _, pool := range e.pools {
 		pool.start()
 	}
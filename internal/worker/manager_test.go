@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"gopher-cafe/internal/clock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+)
+
+func TestEquipPoolManagerRegister(t *testing.T) {
+	clk := clock.NewVirtual(time.UnixMilli(0))
+	manager := NewEquipPoolManager(1, clk)
+	manager.Register(coffeeshop.EquipGrinder, 1)
+	manager.StartAll()
+	defer manager.StopAll()
+
+	pool, err := manager.GetWorkerPool(coffeeshop.EquipGrinder)
+	assert.NoError(t, err)
+
+	// registering again resizes the pool in place, keeping its job in flight
+	done := submit(t.Context(), pool, Job{OrderID: 1, Timer: 20 * time.Millisecond})
+	waitTimers(t, clk, 1)
+
+	manager.Register(coffeeshop.EquipGrinder, 2)
+
+	again, err := manager.GetWorkerPool(coffeeshop.EquipGrinder)
+	assert.NoError(t, err)
+	assert.Same(t, pool, again)
+	assert.Equal(t, uint8(2), manager.Workers()[coffeeshop.EquipGrinder])
+	clk.Advance(20 * time.Millisecond)
+	assert.NoError(t, (<-done).err)
+
+	// pools registered after the start are started right away
+	manager.Register(coffeeshop.EquipWhisk, 1)
+	whisk, err := manager.GetWorkerPool(coffeeshop.EquipWhisk)
+	assert.NoError(t, err)
+	done = submit(t.Context(), whisk, Job{OrderID: 2, Timer: time.Millisecond})
+	waitTimers(t, clk, 1)
+	clk.Advance(time.Millisecond)
+	assert.NoError(t, (<-done).err)
+
+	assert.ErrorIs(t, manager.Resize(coffeeshop.EquipBlender, 1), apperrors.ErrNoWorkerPool)
+}
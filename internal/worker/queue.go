@@ -0,0 +1,46 @@
+package worker
+
+import "time"
+
+// queuedJob is a job waiting for a free worker of the pool.
+type queuedJob struct {
+	input    JobInput
+	queuedAt time.Time
+	key      int64  // from the job priority, the lowest goes first
+	seq      uint64 // breaks ties in arrival order
+	index    int    // in the queue, -1 once taken out of it
+}
+
+// jobQueue is a container/heap of the waiting jobs, served by the key of
+// their priority.
+type jobQueue []*queuedJob
+
+func (q jobQueue) Len() int { return len(q) }
+
+func (q jobQueue) Less(i, j int) bool {
+	if q[i].key != q[j].key {
+		return q[i].key < q[j].key
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *jobQueue) Push(x any) {
+	job := x.(*queuedJob)
+	job.index = len(*q)
+	*q = append(*q, job)
+}
+
+func (q *jobQueue) Pop() any {
+	old := *q
+	job := old[len(old)-1]
+	old[len(old)-1] = nil
+	job.index = -1
+	*q = old[:len(old)-1]
+	return job
+}
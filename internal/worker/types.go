@@ -1,18 +1,66 @@
 package worker
 
-import "time"
+import (
+	"context"
+	"time"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+)
 
 type Job struct {
-	OrderID int64
-	Timer   time.Duration
+	OrderID  int64
+	Timer    time.Duration
+	Priority entity.Priority
 }
 
 type JobInput struct {
+	Ctx    context.Context
 	Job    Job
 	Output chan JobOutput
 }
 
+// JobOutput tells which worker served the job and when. QueuedAt is when the
+// job started waiting for a free worker, AcquiredAt when a worker took it and
+// ReleasedAt when the worker was done with it.
 type JobOutput struct {
-	Job Job
-	Err error
+	Job        Job
+	Err        error
+	WorkerID   int
+	QueuedAt   time.Time
+	AcquiredAt time.Time
+	ReleasedAt time.Time
+}
+
+// PoolStats is a point-in-time view of a worker pool. Durations are
+// accumulated since the pool started.
+type PoolStats struct {
+	Workers        int
+	Busy           int
+	QueueDepth     int
+	JobsCompleted  int64
+	WaitTime       time.Duration   // spent by jobs waiting for a free worker
+	ProcessingTime time.Duration   // spent by workers processing jobs
+	WorkerBusyTime []time.Duration // by worker ID, including retired workers
+	WorkerTime     time.Duration   // summed over the workers the pool had over time
+	Uptime         time.Duration
+	Broken         int // workers being repaired
+	Cleaning       int // workers being cleaned
+	Breakdowns     int64
+	Cleanings      int64
+	RepairTime     time.Duration // spent by workers being repaired
+	CleaningTime   time.Duration // spent by workers being cleaned
+}
+
+// Downtime is the worker time lost to repairs and cleanings.
+func (s PoolStats) Downtime() time.Duration {
+	return s.RepairTime + s.CleaningTime
+}
+
+// Utilization is the share of the worker time spent processing jobs.
+func (s PoolStats) Utilization() float64 {
+	if s.WorkerTime <= 0 {
+		return 0
+	}
+
+	return float64(s.ProcessingTime) / float64(s.WorkerTime)
 }
@@ -1,92 +1,472 @@
 package worker
 
 import (
+	"container/heap"
 	"context"
 	"errors"
+	"gopher-cafe/internal/clock"
+	"math/rand/v2"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+
 	"github.com/ajaibid/coin-common-golang/logger"
 )
 
+// WorkerPool serves the jobs waiting for a free worker by priority, see
+// entity.Priority.QueueKey.
 type WorkerPool struct {
-	name       string
-	jobs       chan JobInput
-	wg         sync.WaitGroup
-	ctx        context.Context
-	cancel     context.CancelFunc
+	name   string
+	ready  chan struct{} // signals the idle workers that a job is queued
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+	clock  clock.Clock
+
+	mu         sync.Mutex
+	queue      jobQueue
+	seq        uint64 // jobs queued so far
 	numWorkers uint8
+	started    bool
+	retire     []chan struct{} // closed to retire the worker of the same ID
+	running    int             // workers not stopped yet, including retiring ones
+	startedSum time.Duration   // summed start times of the running workers since startedAt
+	workerTime time.Duration   // summed lifetimes of the stopped workers
+	faults     entity.FaultModel
+	broken     []bool        // by worker ID, whether the worker is being repaired
+	down       chan struct{} // closed while all the workers are broken down
+
+	startedAt time.Time
+
+	busy          atomic.Int64 // workers processing a job
+	jobsCompleted atomic.Int64
+	waitTime      atomic.Int64    // nanoseconds jobs spent waiting for a free worker
+	busyTime      []*atomic.Int64 // nanoseconds each worker spent processing jobs
+	cleaning      atomic.Int64    // workers being cleaned
+	breakdowns    atomic.Int64
+	cleanings     atomic.Int64
+	repairTime    atomic.Int64 // nanoseconds workers spent being repaired
+	cleaningTime  atomic.Int64 // nanoseconds workers spent being cleaned
 }
 
-func NewWorkerPool(name string, workers uint8) *WorkerPool {
+// wear is what a single worker went through since its last maintenance.
+type wear struct {
+	uses           int           // jobs since the last cleaning
+	untilBreakdown time.Duration // processing time left before the next breakdown
+}
+
+func NewWorkerPool(name string, workers uint8, clk clock.Clock) *WorkerPool {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	wp := &WorkerPool{
 		name:       name,
-		jobs:       make(chan JobInput),
+		ready:      make(chan struct{}, 1),
 		ctx:        ctx,
 		cancel:     cancel,
 		numWorkers: workers,
+		clock:      clk,
+		startedAt:  clk.Now(),
+		down:       make(chan struct{}),
 	}
 
 	return wp
 }
 
 func (wp *WorkerPool) start() {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.started {
+		return
+	}
+	wp.started = true
 	for i := range wp.numWorkers {
-		wp.wg.Add(1)
-		go wp.worker(i)
+		wp.spawn(i)
+	}
+}
+
+// spawn starts the worker of the given ID. wp.mu must be held.
+func (wp *WorkerPool) spawn(id uint8) {
+	retire := make(chan struct{})
+	wp.retire = append(wp.retire, retire)
+	if int(id) == len(wp.busyTime) {
+		wp.busyTime = append(wp.busyTime, new(atomic.Int64))
+		wp.broken = append(wp.broken, false)
+	}
+
+	busyTime := wp.busyTime[id]
+
+	wp.running++
+	startedAt := wp.clock.Now().Sub(wp.startedAt)
+	wp.startedSum += startedAt
+
+	wp.wg.Add(1)
+	go func() {
+		defer wp.wg.Done()
+		wp.worker(id, retire, busyTime)
+
+		wp.mu.Lock()
+		defer wp.mu.Unlock()
+		wp.running--
+		wp.startedSum -= startedAt
+		wp.workerTime += wp.clock.Now().Sub(wp.startedAt) - startedAt
+	}()
+}
+
+// Resize grows or shrinks the pool to n workers. Workers are retired from the
+// highest ID down and only once they are done with their current job, so no
+// job is dropped. Retired IDs are reused when the pool grows again.
+func (wp *WorkerPool) Resize(n uint8) error {
+	if n == 0 {
+		return errors.New("a pool needs at least 1 worker")
+	}
+
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.started {
+		for id := wp.numWorkers; id < n; id++ {
+			wp.spawn(id)
+		}
+		for id := n; id < wp.numWorkers; id++ {
+			close(wp.retire[id])
+		}
+		wp.retire = wp.retire[:n]
+	}
+
+	logger.Infof("[%s] resized from %d to %d workers", wp.name, wp.numWorkers, n)
+	wp.numWorkers = n
+	wp.updateDown()
+
+	return nil
+}
+
+// Size returns the number of workers of the pool.
+func (wp *WorkerPool) Size() uint8 {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	return wp.numWorkers
+}
+
+// SetFaults sets how the workers of the pool break down and get cleaned. The
+// workers pick it up after their current job.
+func (wp *WorkerPool) SetFaults(faults entity.FaultModel) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	wp.faults = faults
+	logger.Infof("[%s] faults set to %+v", wp.name, faults)
+}
+
+func (wp *WorkerPool) getFaults() entity.FaultModel {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	return wp.faults
+}
+
+// updateDown closes wp.down once every worker of the pool is broken down and
+// replaces it when one is back. wp.mu must be held.
+func (wp *WorkerPool) updateDown() {
+	broken := 0
+	for id := range wp.numWorkers {
+		if int(id) < len(wp.broken) && wp.broken[id] {
+			broken++
+		}
+	}
+
+	select {
+	case <-wp.down:
+		if broken < int(wp.numWorkers) {
+			wp.down = make(chan struct{})
+		}
+	default:
+		if broken == int(wp.numWorkers) {
+			close(wp.down)
+		}
 	}
 }
 
 func (wp *WorkerPool) stop() {
-	wp.cancel()    // stop signal
-	close(wp.jobs) // stop accepting jobs
-	wp.wg.Wait()   // wait for workers to finish
+	wp.cancel()  // stop signal, the queued jobs fail with ErrPoolClosed
+	wp.wg.Wait() // wait for workers to finish
+}
+
+// enqueue queues a job for the next free worker.
+func (wp *WorkerPool) enqueue(ji JobInput, queuedAt time.Time) *queuedJob {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	wp.seq++
+	qj := &queuedJob{
+		input:    ji,
+		queuedAt: queuedAt,
+		key:      ji.Job.Priority.QueueKey(queuedAt),
+		seq:      wp.seq,
+	}
+	heap.Push(&wp.queue, qj)
+	wp.signal()
+
+	return qj
 }
 
-func (wp *WorkerPool) worker(id uint8) {
-	defer wp.wg.Done()
+// dequeue takes the first job out of the queue, if any.
+func (wp *WorkerPool) dequeue() (JobInput, bool) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.queue.Len() == 0 {
+		return JobInput{}, false
+	}
+
+	qj := heap.Pop(&wp.queue).(*queuedJob)
+	wp.waitTime.Add(int64(wp.clock.Now().Sub(qj.queuedAt)))
+	if wp.queue.Len() > 0 {
+		// a single signal is kept, so pass it on to the next idle worker
+		wp.signal()
+	}
+
+	return qj.input, true
+}
+
+// remove takes a job given up on out of the queue. It returns false when a
+// worker took the job already.
+func (wp *WorkerPool) remove(qj *queuedJob) bool {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if qj.index < 0 {
+		return false
+	}
+
+	heap.Remove(&wp.queue, qj.index)
+	wp.waitTime.Add(int64(wp.clock.Now().Sub(qj.queuedAt)))
+
+	return true
+}
 
+// signal wakes an idle worker up, unless one is about to wake up already.
+func (wp *WorkerPool) signal() {
+	select {
+	case wp.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (wp *WorkerPool) worker(id uint8, retire <-chan struct{}, busyTime *atomic.Int64) {
+	var w wear
 	for {
 		select {
 		case <-wp.ctx.Done():
 			logger.Debugf("[%s] worker %d stopped", wp.name, id)
 			return
 
-		case job, ok := <-wp.jobs:
+		case <-retire:
+			logger.Debugf("[%s] worker %d retired", wp.name, id)
+			return
+
+		case <-wp.ready:
+			job, ok := wp.dequeue()
 			if !ok {
-				logger.Debugf("[%s] worker %d stopped, got channel closed", wp.name, id)
-				return
+				continue
 			}
 			logger.Debugf("[%s] worker %d doing job: %v start", wp.name, id, job.Job)
-			time.Sleep(job.Job.Timer)
+			wp.busy.Add(1)
+			acquiredAt := wp.clock.Now()
+			err := wp.process(job)
+			releasedAt := wp.clock.Now()
+			busyTime.Add(int64(releasedAt.Sub(acquiredAt)))
+			wp.busy.Add(-1)
+			if err == nil {
+				wp.jobsCompleted.Add(1)
+			}
 			job.Output <- JobOutput{
-				job.Job,
-				nil,
+				Job:        job.Job,
+				Err:        err,
+				WorkerID:   int(id),
+				AcquiredAt: acquiredAt,
+				ReleasedAt: releasedAt,
+			}
+			logger.Debugf("[%s] worker %d doing job: %v finish, err: %v", wp.name, id, job.Job, err)
+
+			if !wp.maintain(id, &w, releasedAt.Sub(acquiredAt), retire) {
+				logger.Debugf("[%s] worker %d stopped during maintenance", wp.name, id)
+				return
 			}
-			logger.Debugf("[%s] worker %d doing job: %v finish", wp.name, id, job.Job)
 		}
 	}
 }
 
-func (wp *WorkerPool) Submit(job Job) (JobOutput, error) {
-	ji := JobInput{
-		Job:    job,
-		Output: make(chan JobOutput),
+// maintain wears the worker by a job that took busy, then repairs the worker
+// if it broke down and cleans it if it is due. Breakdowns happen after a job
+// rather than during it, so the job at hand is never lost. It returns false
+// when the worker was retired or the pool stopped meanwhile.
+func (wp *WorkerPool) maintain(id uint8, w *wear, busy time.Duration, retire <-chan struct{}) bool {
+	faults := wp.getFaults()
+
+	if faults.MTBF > 0 {
+		if w.untilBreakdown <= 0 {
+			// breakdowns come at random, at a constant rate over the processing time
+			w.untilBreakdown = time.Duration(rand.ExpFloat64() * float64(faults.MTBF))
+		}
+		w.untilBreakdown -= busy
+		if w.untilBreakdown <= 0 && !wp.repair(id, faults.RepairTime, retire) {
+			return false
+		}
+	}
+
+	w.uses++
+	if faults.CleanEvery > 0 && w.uses >= faults.CleanEvery {
+		w.uses = 0
+		return wp.clean(id, faults.CleaningTime, retire)
 	}
 
+	return true
+}
+
+func (wp *WorkerPool) repair(id uint8, d time.Duration, retire <-chan struct{}) bool {
+	logger.Infof("[%s] worker %d broke down, repairing for %s", wp.name, id, d)
+
+	wp.mu.Lock()
+	wp.broken[id] = true
+	wp.updateDown()
+	wp.mu.Unlock()
+
+	wp.breakdowns.Add(1)
+	ok := wp.pause(d, &wp.repairTime, retire)
+
+	wp.mu.Lock()
+	wp.broken[id] = false
+	wp.updateDown()
+	wp.mu.Unlock()
+
+	logger.Infof("[%s] worker %d repaired", wp.name, id)
+	return ok
+}
+
+func (wp *WorkerPool) clean(id uint8, d time.Duration, retire <-chan struct{}) bool {
+	logger.Debugf("[%s] worker %d cleaning for %s", wp.name, id, d)
+
+	wp.cleaning.Add(1)
+	defer wp.cleaning.Add(-1)
+
+	wp.cleanings.Add(1)
+	return wp.pause(d, &wp.cleaningTime, retire)
+}
+
+// pause keeps the worker from taking jobs for d, adding the time it was away to
+// downtime.
+func (wp *WorkerPool) pause(d time.Duration, downtime *atomic.Int64, retire <-chan struct{}) bool {
+	since := wp.clock.Now()
+	defer func() {
+		downtime.Add(int64(wp.clock.Now().Sub(since)))
+	}()
+
 	select {
-	case wp.jobs <- ji:
+	case <-wp.clock.After(d):
+		return true
+	case <-retire:
+		return false
 	case <-wp.ctx.Done():
-		return JobOutput{}, errors.New("pool closed")
+		return false
 	}
+}
 
-	// wait for response
+// process occupies the worker for the job duration, giving the equipment back
+// early when either the job owner or the pool is done.
+func (wp *WorkerPool) process(job JobInput) error {
 	select {
-	case res := <-ji.Output:
-		return res, nil
+	case <-wp.clock.After(job.Job.Timer):
+		return nil
+	case <-job.Ctx.Done():
+		return apperrors.ErrJobCancelled
 	case <-wp.ctx.Done():
-		return JobOutput{}, errors.New("shutdown")
+		return apperrors.ErrPoolClosed
+	}
+}
+
+// Submit blocks until a worker has finished the job. It returns
+// ErrJobCancelled as soon as ctx is done, whether the job is still waiting for
+// a free worker or already being processed. Under the BreakdownFail policy it
+// returns ErrBrokenDown instead of waiting while every worker is broken down.
+// Jobs of a higher priority are taken first by the free workers.
+func (wp *WorkerPool) Submit(ctx context.Context, job Job) (JobOutput, error) {
+	if ctx.Err() != nil {
+		return JobOutput{}, apperrors.ErrJobCancelled
+	}
+
+	var down chan struct{} // never ready unless the policy is to fail
+	wp.mu.Lock()
+	if wp.faults.OnBreakdown == entity.BreakdownFail {
+		down = wp.down
 	}
+	wp.mu.Unlock()
+
+	ji := JobInput{
+		Ctx:    ctx,
+		Job:    job,
+		Output: make(chan JobOutput, 1), // worker must never block on an abandoned job
+	}
+
+	queuedAt := wp.clock.Now()
+	qj := wp.enqueue(ji, queuedAt)
+
+	for {
+		select {
+		case res := <-ji.Output:
+			res.QueuedAt = queuedAt
+			return res, res.Err
+		case <-ctx.Done():
+			wp.remove(qj)
+			return JobOutput{}, apperrors.ErrJobCancelled
+		case <-down:
+			if wp.remove(qj) {
+				return JobOutput{}, apperrors.ErrBrokenDown
+			}
+			// a worker is on the job already
+			down = nil
+		case <-wp.ctx.Done():
+			wp.remove(qj)
+			return JobOutput{}, apperrors.ErrPoolClosed
+		}
+	}
+}
+
+func (wp *WorkerPool) Stats() PoolStats {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	uptime := wp.clock.Now().Sub(wp.startedAt)
+	stats := PoolStats{
+		Workers:        int(wp.numWorkers),
+		Busy:           int(wp.busy.Load()),
+		QueueDepth:     wp.queue.Len(),
+		JobsCompleted:  wp.jobsCompleted.Load(),
+		WaitTime:       time.Duration(wp.waitTime.Load()),
+		WorkerBusyTime: make([]time.Duration, len(wp.busyTime)),
+		WorkerTime:     wp.workerTime + uptime*time.Duration(wp.running) - wp.startedSum,
+		Uptime:         uptime,
+		Cleaning:       int(wp.cleaning.Load()),
+		Breakdowns:     wp.breakdowns.Load(),
+		Cleanings:      wp.cleanings.Load(),
+		RepairTime:     time.Duration(wp.repairTime.Load()),
+		CleaningTime:   time.Duration(wp.cleaningTime.Load()),
+	}
+
+	for _, broken := range wp.broken {
+		if broken {
+			stats.Broken++
+		}
+	}
+
+	for i := range wp.busyTime {
+		stats.WorkerBusyTime[i] = time.Duration(wp.busyTime[i].Load())
+		stats.ProcessingTime += stats.WorkerBusyTime[i]
+	}
+
+	return stats
 }
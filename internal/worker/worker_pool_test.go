@@ -0,0 +1,325 @@
+package worker
+
+import (
+	"context"
+	"gopher-cafe/internal/clock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	entity "gopher-cafe/internal/entity/coffeeshop"
+	apperrors "gopher-cafe/internal/errors"
+)
+
+// submitted is the outcome of a job submitted in the background.
+type submitted struct {
+	out JobOutput
+	err error
+}
+
+func submit(ctx context.Context, pool *WorkerPool, job Job) <-chan submitted {
+	done := make(chan submitted, 1)
+	go func() {
+		out, err := pool.Submit(ctx, job)
+		done <- submitted{out: out, err: err}
+	}()
+
+	return done
+}
+
+// waitTimers waits until n timers are set on clk, so that moving the time
+// does not leave a worker behind.
+func waitTimers(t *testing.T, clk *clock.Virtual, n int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		return clk.Pending() == n
+	}, time.Second, time.Millisecond)
+}
+
+func waitQueued(t *testing.T, pool *WorkerPool, n int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		return pool.Stats().QueueDepth == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestWorkerPoolSubmit(t *testing.T) {
+	tests := []struct {
+		name    string
+		busy    bool // occupy the only worker so the job has to wait in queue
+		cancel  bool
+		wantErr error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:    "cancelled while processing",
+			cancel:  true,
+			wantErr: apperrors.ErrJobCancelled,
+		},
+		{
+			name:    "cancelled while waiting for a worker",
+			busy:    true,
+			cancel:  true,
+			wantErr: apperrors.ErrJobCancelled,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clk := clock.NewVirtual(time.UnixMilli(0))
+			pool := NewWorkerPool("test", 1, clk)
+			pool.start()
+			defer pool.stop()
+
+			if test.busy {
+				submit(t.Context(), pool, Job{OrderID: 0, Timer: time.Minute})
+				waitTimers(t, clk, 1)
+			}
+
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+
+			done := submit(ctx, pool, Job{OrderID: 1, Timer: 50 * time.Millisecond})
+			if test.busy {
+				waitQueued(t, pool, 1)
+			} else {
+				waitTimers(t, clk, 1)
+			}
+
+			if test.cancel {
+				cancel()
+			} else {
+				clk.Advance(50 * time.Millisecond)
+			}
+
+			res := <-done
+			assert.ErrorIs(t, res.err, test.wantErr)
+			if test.wantErr == nil {
+				assert.Equal(t, 50*time.Millisecond, res.out.ReleasedAt.Sub(res.out.AcquiredAt))
+			}
+			assert.Equal(t, 0, pool.Stats().QueueDepth)
+		})
+	}
+}
+
+func TestWorkerPoolPriority(t *testing.T) {
+	type queued struct {
+		orderID  int64
+		priority entity.Priority
+		atMs     int64
+	}
+
+	tests := []struct {
+		name string
+		jobs []queued // in the order they are submitted
+		want []int64
+	}{
+		{
+			name: "higher classes first",
+			jobs: []queued{
+				{orderID: 1, priority: entity.PriorityWalkIn},
+				{orderID: 2, priority: entity.PriorityMobile},
+				{orderID: 3, priority: entity.PriorityVIP},
+				{orderID: 4, priority: entity.PriorityWalkIn},
+			},
+			want: []int64{3, 2, 1, 4},
+		},
+		{
+			name: "waiting makes up for the class",
+			jobs: []queued{
+				{orderID: 1, priority: entity.PriorityWalkIn, atMs: 0},
+				{orderID: 2, priority: entity.PriorityMobile, atMs: 30},
+				// queued more than 2 classes worth of aging after order 1
+				{orderID: 3, priority: entity.PriorityVIP, atMs: 120},
+				{orderID: 4, priority: entity.PriorityVIP, atMs: 140},
+			},
+			want: []int64{2, 1, 3, 4},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clk := clock.NewVirtual(time.UnixMilli(0))
+			// not started, so the jobs stay queued until taken out below
+			pool := NewWorkerPool("test", 1, clk)
+
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+
+			for i, job := range test.jobs {
+				clk.Set(time.UnixMilli(job.atMs))
+				go pool.Submit(ctx, Job{OrderID: job.orderID, Priority: job.priority})
+				assert.Eventually(t, func() bool {
+					return pool.Stats().QueueDepth == i+1
+				}, time.Second, time.Millisecond)
+			}
+
+			var got []int64
+			for {
+				ji, ok := pool.dequeue()
+				if !ok {
+					break
+				}
+				got = append(got, ji.Job.OrderID)
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestWorkerPoolStats(t *testing.T) {
+	clk := clock.NewVirtual(time.UnixMilli(0))
+	pool := NewWorkerPool("test", 1, clk)
+	pool.start()
+	defer pool.stop()
+
+	// the second job has to wait for the first one to release the worker
+	first := submit(t.Context(), pool, Job{OrderID: 1, Timer: 20 * time.Millisecond})
+	waitTimers(t, clk, 1)
+	second := submit(t.Context(), pool, Job{OrderID: 2, Timer: 20 * time.Millisecond})
+	waitQueued(t, pool, 1)
+
+	clk.Advance(20 * time.Millisecond)
+	assert.NoError(t, (<-first).err)
+	waitTimers(t, clk, 1)
+	clk.Advance(20 * time.Millisecond)
+	assert.NoError(t, (<-second).err)
+
+	stats := pool.Stats()
+	assert.Equal(t, 1, stats.Workers)
+	assert.Equal(t, 0, stats.Busy)
+	assert.Equal(t, 0, stats.QueueDepth)
+	assert.Equal(t, int64(2), stats.JobsCompleted)
+	assert.Equal(t, 40*time.Millisecond, stats.ProcessingTime)
+	assert.Equal(t, 20*time.Millisecond, stats.WaitTime)
+	assert.Equal(t, stats.ProcessingTime, stats.WorkerBusyTime[0])
+	assert.Equal(t, 1.0, stats.Utilization())
+}
+
+func TestWorkerPoolResize(t *testing.T) {
+	clk := clock.NewVirtual(time.UnixMilli(0))
+	pool := NewWorkerPool("test", 2, clk)
+	pool.start()
+	defer pool.stop()
+
+	// both workers are busy when the pool shrinks, the retired one still
+	// finishes its job
+	var results []<-chan submitted
+	for i := range 2 {
+		results = append(results, submit(t.Context(), pool, Job{OrderID: int64(i), Timer: 30 * time.Millisecond}))
+	}
+	waitTimers(t, clk, 2)
+
+	assert.NoError(t, pool.Resize(1))
+	assert.Equal(t, uint8(1), pool.Size())
+	clk.Advance(30 * time.Millisecond)
+	for _, done := range results {
+		assert.NoError(t, (<-done).err)
+	}
+	assert.Eventually(t, func() bool {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		return pool.running == 1
+	}, time.Second, time.Millisecond)
+
+	// a job is only taken by the remaining worker
+	done := submit(t.Context(), pool, Job{OrderID: 3, Timer: time.Millisecond})
+	waitTimers(t, clk, 1)
+	clk.Advance(time.Millisecond)
+	res := <-done
+	assert.NoError(t, res.err)
+	assert.Equal(t, 0, res.out.WorkerID)
+
+	// growing again lets two jobs run side by side
+	assert.NoError(t, pool.Resize(2))
+	results = results[:0]
+	for i := range 2 {
+		results = append(results, submit(t.Context(), pool, Job{OrderID: int64(4 + i), Timer: 30 * time.Millisecond}))
+	}
+	waitTimers(t, clk, 2)
+	clk.Advance(30 * time.Millisecond)
+	for _, done := range results {
+		res := <-done
+		assert.NoError(t, res.err)
+		assert.Equal(t, clk.Now(), res.out.ReleasedAt)
+	}
+
+	stats := pool.Stats()
+	assert.Equal(t, 2, stats.Workers)
+	assert.Equal(t, int64(5), stats.JobsCompleted)
+	assert.LessOrEqual(t, stats.Utilization(), 1.0)
+
+	assert.Error(t, pool.Resize(0))
+}
+
+func TestWorkerPoolFaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		faults   entity.FaultModel
+		wantWait time.Duration // of the job sent while the worker is out
+		wantErr  error
+		check    func(stats PoolStats) bool
+	}{
+		{
+			name:     "cleaned after every job",
+			faults:   entity.FaultModel{CleanEvery: 1, CleaningTime: 30 * time.Millisecond},
+			wantWait: 30 * time.Millisecond,
+			check: func(stats PoolStats) bool {
+				return stats.Cleanings == 2 && stats.Breakdowns == 0 && stats.CleaningTime == 30*time.Millisecond
+			},
+		},
+		{
+			name: "waits for the repair",
+			// breaks down after every job
+			faults:   entity.FaultModel{MTBF: time.Nanosecond, RepairTime: 30 * time.Millisecond, OnBreakdown: entity.BreakdownWait},
+			wantWait: 30 * time.Millisecond,
+			check: func(stats PoolStats) bool {
+				return stats.Breakdowns == 2 && stats.RepairTime == 30*time.Millisecond
+			},
+		},
+		{
+			name:    "fails while broken down",
+			faults:  entity.FaultModel{MTBF: time.Nanosecond, RepairTime: time.Minute, OnBreakdown: entity.BreakdownFail},
+			wantErr: apperrors.ErrBrokenDown,
+			check: func(stats PoolStats) bool {
+				return stats.Broken == 1 && stats.Downtime() == stats.RepairTime
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clk := clock.NewVirtual(time.UnixMilli(0))
+			pool := NewWorkerPool("test", 1, clk)
+			pool.SetFaults(test.faults)
+			pool.start()
+			defer pool.stop()
+
+			first := submit(t.Context(), pool, Job{OrderID: 1, Timer: time.Millisecond})
+			waitTimers(t, clk, 1)
+			clk.Advance(time.Millisecond)
+			assert.NoError(t, (<-first).err)
+
+			// the worker goes down after the job
+			waitTimers(t, clk, 1)
+			second := submit(t.Context(), pool, Job{OrderID: 2, Timer: time.Millisecond})
+			if test.wantErr == nil {
+				waitQueued(t, pool, 1)
+				clk.Advance(test.wantWait)
+				waitTimers(t, clk, 1)
+				clk.Advance(time.Millisecond)
+			}
+
+			res := <-second
+			assert.ErrorIs(t, res.err, test.wantErr)
+			if test.wantErr == nil {
+				assert.Equal(t, test.wantWait, res.out.AcquiredAt.Sub(res.out.QueuedAt))
+			}
+			assert.Eventually(t, func() bool {
+				return test.check(pool.Stats())
+			}, time.Second, time.Millisecond)
+		})
+	}
+}
@@ -0,0 +1,748 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: pkg/proto/cafe/v1/admin.proto
+
+package cafepb
+
+import (
+	v1 "github.com/rexyajaib/gopher-cafe/pkg/gen/go/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ResizeEquipmentRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Equipment v1.EquipmentType       `protobuf:"varint,1,opt,name=equipment,proto3,enum=pkg.proto.v1.EquipmentType" json:"equipment,omitempty"`
+	// workers must be between 1 and 255.
+	Workers       uint32 `protobuf:"varint,2,opt,name=workers,proto3" json:"workers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResizeEquipmentRequest) Reset() {
+	*x = ResizeEquipmentRequest{}
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResizeEquipmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResizeEquipmentRequest) ProtoMessage() {}
+
+func (x *ResizeEquipmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResizeEquipmentRequest.ProtoReflect.Descriptor instead.
+func (*ResizeEquipmentRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ResizeEquipmentRequest) GetEquipment() v1.EquipmentType {
+	if x != nil {
+		return x.Equipment
+	}
+	return v1.EquipmentType(0)
+}
+
+func (x *ResizeEquipmentRequest) GetWorkers() uint32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+type ResizeEquipmentResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Equipment       v1.EquipmentType       `protobuf:"varint,1,opt,name=equipment,proto3,enum=pkg.proto.v1.EquipmentType" json:"equipment,omitempty"`
+	PreviousWorkers uint32                 `protobuf:"varint,2,opt,name=previous_workers,json=previousWorkers,proto3" json:"previous_workers,omitempty"`
+	Workers         uint32                 `protobuf:"varint,3,opt,name=workers,proto3" json:"workers,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ResizeEquipmentResponse) Reset() {
+	*x = ResizeEquipmentResponse{}
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResizeEquipmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResizeEquipmentResponse) ProtoMessage() {}
+
+func (x *ResizeEquipmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResizeEquipmentResponse.ProtoReflect.Descriptor instead.
+func (*ResizeEquipmentResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ResizeEquipmentResponse) GetEquipment() v1.EquipmentType {
+	if x != nil {
+		return x.Equipment
+	}
+	return v1.EquipmentType(0)
+}
+
+func (x *ResizeEquipmentResponse) GetPreviousWorkers() uint32 {
+	if x != nil {
+		return x.PreviousWorkers
+	}
+	return 0
+}
+
+func (x *ResizeEquipmentResponse) GetWorkers() uint32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+type IngredientLevel struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ingredient is named like in the menu file, e.g. "milk".
+	Ingredient string `protobuf:"bytes,1,opt,name=ingredient,proto3" json:"ingredient,omitempty"`
+	Quantity   int64  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// low_stock is the quantity below which the ingredient is worth a restock.
+	LowStock      int64 `protobuf:"varint,3,opt,name=low_stock,json=lowStock,proto3" json:"low_stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngredientLevel) Reset() {
+	*x = IngredientLevel{}
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngredientLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngredientLevel) ProtoMessage() {}
+
+func (x *IngredientLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngredientLevel.ProtoReflect.Descriptor instead.
+func (*IngredientLevel) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *IngredientLevel) GetIngredient() string {
+	if x != nil {
+		return x.Ingredient
+	}
+	return ""
+}
+
+func (x *IngredientLevel) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *IngredientLevel) GetLowStock() int64 {
+	if x != nil {
+		return x.LowStock
+	}
+	return 0
+}
+
+type RestockRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Ingredient string                 `protobuf:"bytes,1,opt,name=ingredient,proto3" json:"ingredient,omitempty"`
+	// quantity must be positive.
+	Quantity      int64 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestockRequest) Reset() {
+	*x = RestockRequest{}
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestockRequest) ProtoMessage() {}
+
+func (x *RestockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestockRequest.ProtoReflect.Descriptor instead.
+func (*RestockRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RestockRequest) GetIngredient() string {
+	if x != nil {
+		return x.Ingredient
+	}
+	return ""
+}
+
+func (x *RestockRequest) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type RestockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Level         *IngredientLevel       `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestockResponse) Reset() {
+	*x = RestockResponse{}
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestockResponse) ProtoMessage() {}
+
+func (x *RestockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestockResponse.ProtoReflect.Descriptor instead.
+func (*RestockResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RestockResponse) GetLevel() *IngredientLevel {
+	if x != nil {
+		return x.Level
+	}
+	return nil
+}
+
+type GetInventoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInventoryRequest) Reset() {
+	*x = GetInventoryRequest{}
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInventoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInventoryRequest) ProtoMessage() {}
+
+func (x *GetInventoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInventoryRequest.ProtoReflect.Descriptor instead.
+func (*GetInventoryRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_admin_proto_rawDescGZIP(), []int{5}
+}
+
+type GetInventoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ingredients   []*IngredientLevel     `protobuf:"bytes,1,rep,name=ingredients,proto3" json:"ingredients,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInventoryResponse) Reset() {
+	*x = GetInventoryResponse{}
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInventoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInventoryResponse) ProtoMessage() {}
+
+func (x *GetInventoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInventoryResponse.ProtoReflect.Descriptor instead.
+func (*GetInventoryResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetInventoryResponse) GetIngredients() []*IngredientLevel {
+	if x != nil {
+		return x.Ingredients
+	}
+	return nil
+}
+
+type EquipmentStats struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Equipment   v1.EquipmentType       `protobuf:"varint,1,opt,name=equipment,proto3,enum=pkg.proto.v1.EquipmentType" json:"equipment,omitempty"`
+	Workers     uint32                 `protobuf:"varint,2,opt,name=workers,proto3" json:"workers,omitempty"`
+	BusyWorkers uint32                 `protobuf:"varint,3,opt,name=busy_workers,json=busyWorkers,proto3" json:"busy_workers,omitempty"`
+	// queue_depth is the number of steps waiting for a free worker.
+	QueueDepth    uint32 `protobuf:"varint,4,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`
+	JobsCompleted int64  `protobuf:"varint,5,opt,name=jobs_completed,json=jobsCompleted,proto3" json:"jobs_completed,omitempty"`
+	// wait_ms is the time the steps spent waiting for a free worker and
+	// processing_ms the time they spent being processed, summed since startup.
+	WaitMs       int64 `protobuf:"varint,6,opt,name=wait_ms,json=waitMs,proto3" json:"wait_ms,omitempty"`
+	ProcessingMs int64 `protobuf:"varint,7,opt,name=processing_ms,json=processingMs,proto3" json:"processing_ms,omitempty"`
+	// worker_busy_ms is the time each worker spent processing, by worker ID.
+	WorkerBusyMs []int64 `protobuf:"varint,8,rep,packed,name=worker_busy_ms,json=workerBusyMs,proto3" json:"worker_busy_ms,omitempty"`
+	// utilization is the share of the worker time spent processing, from 0
+	// to 1.
+	Utilization     float64 `protobuf:"fixed64,9,opt,name=utilization,proto3" json:"utilization,omitempty"`
+	BrokenWorkers   uint32  `protobuf:"varint,10,opt,name=broken_workers,json=brokenWorkers,proto3" json:"broken_workers,omitempty"`
+	CleaningWorkers uint32  `protobuf:"varint,11,opt,name=cleaning_workers,json=cleaningWorkers,proto3" json:"cleaning_workers,omitempty"`
+	Breakdowns      int64   `protobuf:"varint,12,opt,name=breakdowns,proto3" json:"breakdowns,omitempty"`
+	Cleanings       int64   `protobuf:"varint,13,opt,name=cleanings,proto3" json:"cleanings,omitempty"`
+	RepairMs        int64   `protobuf:"varint,14,opt,name=repair_ms,json=repairMs,proto3" json:"repair_ms,omitempty"`
+	CleaningMs      int64   `protobuf:"varint,15,opt,name=cleaning_ms,json=cleaningMs,proto3" json:"cleaning_ms,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EquipmentStats) Reset() {
+	*x = EquipmentStats{}
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EquipmentStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EquipmentStats) ProtoMessage() {}
+
+func (x *EquipmentStats) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EquipmentStats.ProtoReflect.Descriptor instead.
+func (*EquipmentStats) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *EquipmentStats) GetEquipment() v1.EquipmentType {
+	if x != nil {
+		return x.Equipment
+	}
+	return v1.EquipmentType(0)
+}
+
+func (x *EquipmentStats) GetWorkers() uint32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetBusyWorkers() uint32 {
+	if x != nil {
+		return x.BusyWorkers
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetQueueDepth() uint32 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetJobsCompleted() int64 {
+	if x != nil {
+		return x.JobsCompleted
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetWaitMs() int64 {
+	if x != nil {
+		return x.WaitMs
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetProcessingMs() int64 {
+	if x != nil {
+		return x.ProcessingMs
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetWorkerBusyMs() []int64 {
+	if x != nil {
+		return x.WorkerBusyMs
+	}
+	return nil
+}
+
+func (x *EquipmentStats) GetUtilization() float64 {
+	if x != nil {
+		return x.Utilization
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetBrokenWorkers() uint32 {
+	if x != nil {
+		return x.BrokenWorkers
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetCleaningWorkers() uint32 {
+	if x != nil {
+		return x.CleaningWorkers
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetBreakdowns() int64 {
+	if x != nil {
+		return x.Breakdowns
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetCleanings() int64 {
+	if x != nil {
+		return x.Cleanings
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetRepairMs() int64 {
+	if x != nil {
+		return x.RepairMs
+	}
+	return 0
+}
+
+func (x *EquipmentStats) GetCleaningMs() int64 {
+	if x != nil {
+		return x.CleaningMs
+	}
+	return 0
+}
+
+type GetEquipmentStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEquipmentStatsRequest) Reset() {
+	*x = GetEquipmentStatsRequest{}
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEquipmentStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEquipmentStatsRequest) ProtoMessage() {}
+
+func (x *GetEquipmentStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEquipmentStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetEquipmentStatsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+type GetEquipmentStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// equipment is sorted by equipment type.
+	Equipment     []*EquipmentStats `protobuf:"bytes,1,rep,name=equipment,proto3" json:"equipment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEquipmentStatsResponse) Reset() {
+	*x = GetEquipmentStatsResponse{}
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEquipmentStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEquipmentStatsResponse) ProtoMessage() {}
+
+func (x *GetEquipmentStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEquipmentStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetEquipmentStatsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetEquipmentStatsResponse) GetEquipment() []*EquipmentStats {
+	if x != nil {
+		return x.Equipment
+	}
+	return nil
+}
+
+var File_pkg_proto_cafe_v1_admin_proto protoreflect.FileDescriptor
+
+const file_pkg_proto_cafe_v1_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x1dpkg/proto/cafe/v1/admin.proto\x12\x11pkg.proto.cafe.v1\x1a\x1apkg/proto/v1/message.proto\"m\n" +
+	"\x16ResizeEquipmentRequest\x129\n" +
+	"\tequipment\x18\x01 \x01(\x0e2\x1b.pkg.proto.v1.EquipmentTypeR\tequipment\x12\x18\n" +
+	"\aworkers\x18\x02 \x01(\rR\aworkers\"\x99\x01\n" +
+	"\x17ResizeEquipmentResponse\x129\n" +
+	"\tequipment\x18\x01 \x01(\x0e2\x1b.pkg.proto.v1.EquipmentTypeR\tequipment\x12)\n" +
+	"\x10previous_workers\x18\x02 \x01(\rR\x0fpreviousWorkers\x12\x18\n" +
+	"\aworkers\x18\x03 \x01(\rR\aworkers\"j\n" +
+	"\x0fIngredientLevel\x12\x1e\n" +
+	"\n" +
+	"ingredient\x18\x01 \x01(\tR\n" +
+	"ingredient\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\x12\x1b\n" +
+	"\tlow_stock\x18\x03 \x01(\x03R\blowStock\"L\n" +
+	"\x0eRestockRequest\x12\x1e\n" +
+	"\n" +
+	"ingredient\x18\x01 \x01(\tR\n" +
+	"ingredient\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\"K\n" +
+	"\x0fRestockResponse\x128\n" +
+	"\x05level\x18\x01 \x01(\v2\".pkg.proto.cafe.v1.IngredientLevelR\x05level\"\x15\n" +
+	"\x13GetInventoryRequest\"\\\n" +
+	"\x14GetInventoryResponse\x12D\n" +
+	"\vingredients\x18\x01 \x03(\v2\".pkg.proto.cafe.v1.IngredientLevelR\vingredients\"\xa4\x04\n" +
+	"\x0eEquipmentStats\x129\n" +
+	"\tequipment\x18\x01 \x01(\x0e2\x1b.pkg.proto.v1.EquipmentTypeR\tequipment\x12\x18\n" +
+	"\aworkers\x18\x02 \x01(\rR\aworkers\x12!\n" +
+	"\fbusy_workers\x18\x03 \x01(\rR\vbusyWorkers\x12\x1f\n" +
+	"\vqueue_depth\x18\x04 \x01(\rR\n" +
+	"queueDepth\x12%\n" +
+	"\x0ejobs_completed\x18\x05 \x01(\x03R\rjobsCompleted\x12\x17\n" +
+	"\await_ms\x18\x06 \x01(\x03R\x06waitMs\x12#\n" +
+	"\rprocessing_ms\x18\a \x01(\x03R\fprocessingMs\x12$\n" +
+	"\x0eworker_busy_ms\x18\b \x03(\x03R\fworkerBusyMs\x12 \n" +
+	"\vutilization\x18\t \x01(\x01R\vutilization\x12%\n" +
+	"\x0ebroken_workers\x18\n" +
+	" \x01(\rR\rbrokenWorkers\x12)\n" +
+	"\x10cleaning_workers\x18\v \x01(\rR\x0fcleaningWorkers\x12\x1e\n" +
+	"\n" +
+	"breakdowns\x18\f \x01(\x03R\n" +
+	"breakdowns\x12\x1c\n" +
+	"\tcleanings\x18\r \x01(\x03R\tcleanings\x12\x1b\n" +
+	"\trepair_ms\x18\x0e \x01(\x03R\brepairMs\x12\x1f\n" +
+	"\vcleaning_ms\x18\x0f \x01(\x03R\n" +
+	"cleaningMs\"\x1a\n" +
+	"\x18GetEquipmentStatsRequest\"\\\n" +
+	"\x19GetEquipmentStatsResponse\x12?\n" +
+	"\tequipment\x18\x01 \x03(\v2!.pkg.proto.cafe.v1.EquipmentStatsR\tequipment2\x9f\x03\n" +
+	"\x10CafeAdminService\x12h\n" +
+	"\x0fResizeEquipment\x12).pkg.proto.cafe.v1.ResizeEquipmentRequest\x1a*.pkg.proto.cafe.v1.ResizeEquipmentResponse\x12P\n" +
+	"\aRestock\x12!.pkg.proto.cafe.v1.RestockRequest\x1a\".pkg.proto.cafe.v1.RestockResponse\x12_\n" +
+	"\fGetInventory\x12&.pkg.proto.cafe.v1.GetInventoryRequest\x1a'.pkg.proto.cafe.v1.GetInventoryResponse\x12n\n" +
+	"\x11GetEquipmentStats\x12+.pkg.proto.cafe.v1.GetEquipmentStatsRequest\x1a,.pkg.proto.cafe.v1.GetEquipmentStatsResponseB'Z%gopher-cafe/pkg/gen/go/cafe/v1;cafepbb\x06proto3"
+
+var (
+	file_pkg_proto_cafe_v1_admin_proto_rawDescOnce sync.Once
+	file_pkg_proto_cafe_v1_admin_proto_rawDescData []byte
+)
+
+func file_pkg_proto_cafe_v1_admin_proto_rawDescGZIP() []byte {
+	file_pkg_proto_cafe_v1_admin_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_cafe_v1_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_proto_cafe_v1_admin_proto_rawDesc), len(file_pkg_proto_cafe_v1_admin_proto_rawDesc)))
+	})
+	return file_pkg_proto_cafe_v1_admin_proto_rawDescData
+}
+
+var file_pkg_proto_cafe_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_pkg_proto_cafe_v1_admin_proto_goTypes = []any{
+	(*ResizeEquipmentRequest)(nil),    // 0: pkg.proto.cafe.v1.ResizeEquipmentRequest
+	(*ResizeEquipmentResponse)(nil),   // 1: pkg.proto.cafe.v1.ResizeEquipmentResponse
+	(*IngredientLevel)(nil),           // 2: pkg.proto.cafe.v1.IngredientLevel
+	(*RestockRequest)(nil),            // 3: pkg.proto.cafe.v1.RestockRequest
+	(*RestockResponse)(nil),           // 4: pkg.proto.cafe.v1.RestockResponse
+	(*GetInventoryRequest)(nil),       // 5: pkg.proto.cafe.v1.GetInventoryRequest
+	(*GetInventoryResponse)(nil),      // 6: pkg.proto.cafe.v1.GetInventoryResponse
+	(*EquipmentStats)(nil),            // 7: pkg.proto.cafe.v1.EquipmentStats
+	(*GetEquipmentStatsRequest)(nil),  // 8: pkg.proto.cafe.v1.GetEquipmentStatsRequest
+	(*GetEquipmentStatsResponse)(nil), // 9: pkg.proto.cafe.v1.GetEquipmentStatsResponse
+	(v1.EquipmentType)(0),             // 10: pkg.proto.v1.EquipmentType
+}
+var file_pkg_proto_cafe_v1_admin_proto_depIdxs = []int32{
+	10, // 0: pkg.proto.cafe.v1.ResizeEquipmentRequest.equipment:type_name -> pkg.proto.v1.EquipmentType
+	10, // 1: pkg.proto.cafe.v1.ResizeEquipmentResponse.equipment:type_name -> pkg.proto.v1.EquipmentType
+	2,  // 2: pkg.proto.cafe.v1.RestockResponse.level:type_name -> pkg.proto.cafe.v1.IngredientLevel
+	2,  // 3: pkg.proto.cafe.v1.GetInventoryResponse.ingredients:type_name -> pkg.proto.cafe.v1.IngredientLevel
+	10, // 4: pkg.proto.cafe.v1.EquipmentStats.equipment:type_name -> pkg.proto.v1.EquipmentType
+	7,  // 5: pkg.proto.cafe.v1.GetEquipmentStatsResponse.equipment:type_name -> pkg.proto.cafe.v1.EquipmentStats
+	0,  // 6: pkg.proto.cafe.v1.CafeAdminService.ResizeEquipment:input_type -> pkg.proto.cafe.v1.ResizeEquipmentRequest
+	3,  // 7: pkg.proto.cafe.v1.CafeAdminService.Restock:input_type -> pkg.proto.cafe.v1.RestockRequest
+	5,  // 8: pkg.proto.cafe.v1.CafeAdminService.GetInventory:input_type -> pkg.proto.cafe.v1.GetInventoryRequest
+	8,  // 9: pkg.proto.cafe.v1.CafeAdminService.GetEquipmentStats:input_type -> pkg.proto.cafe.v1.GetEquipmentStatsRequest
+	1,  // 10: pkg.proto.cafe.v1.CafeAdminService.ResizeEquipment:output_type -> pkg.proto.cafe.v1.ResizeEquipmentResponse
+	4,  // 11: pkg.proto.cafe.v1.CafeAdminService.Restock:output_type -> pkg.proto.cafe.v1.RestockResponse
+	6,  // 12: pkg.proto.cafe.v1.CafeAdminService.GetInventory:output_type -> pkg.proto.cafe.v1.GetInventoryResponse
+	9,  // 13: pkg.proto.cafe.v1.CafeAdminService.GetEquipmentStats:output_type -> pkg.proto.cafe.v1.GetEquipmentStatsResponse
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_cafe_v1_admin_proto_init() }
+func file_pkg_proto_cafe_v1_admin_proto_init() {
+	if File_pkg_proto_cafe_v1_admin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_proto_cafe_v1_admin_proto_rawDesc), len(file_pkg_proto_cafe_v1_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_proto_cafe_v1_admin_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_cafe_v1_admin_proto_depIdxs,
+		MessageInfos:      file_pkg_proto_cafe_v1_admin_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_cafe_v1_admin_proto = out.File
+	file_pkg_proto_cafe_v1_admin_proto_goTypes = nil
+	file_pkg_proto_cafe_v1_admin_proto_depIdxs = nil
+}
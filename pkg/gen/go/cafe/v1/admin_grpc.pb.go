@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: pkg/proto/cafe/v1/admin.proto
+
+package cafepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CafeAdminService_ResizeEquipment_FullMethodName   = "/pkg.proto.cafe.v1.CafeAdminService/ResizeEquipment"
+	CafeAdminService_Restock_FullMethodName           = "/pkg.proto.cafe.v1.CafeAdminService/Restock"
+	CafeAdminService_GetInventory_FullMethodName      = "/pkg.proto.cafe.v1.CafeAdminService/GetInventory"
+	CafeAdminService_GetEquipmentStats_FullMethodName = "/pkg.proto.cafe.v1.CafeAdminService/GetEquipmentStats"
+)
+
+// CafeAdminServiceClient is the client API for CafeAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CafeAdminService operates the cafe while it is running.
+type CafeAdminServiceClient interface {
+	// ResizeEquipment sets the number of workers of an equipment. Workers taken
+	// away finish the step they are on first.
+	ResizeEquipment(ctx context.Context, in *ResizeEquipmentRequest, opts ...grpc.CallOption) (*ResizeEquipmentResponse, error)
+	// Restock adds to the stock of an ingredient of the inventory.
+	Restock(ctx context.Context, in *RestockRequest, opts ...grpc.CallOption) (*RestockResponse, error)
+	// GetInventory returns the stock of every ingredient.
+	GetInventory(ctx context.Context, in *GetInventoryRequest, opts ...grpc.CallOption) (*GetInventoryResponse, error)
+	// GetEquipmentStats returns how busy every equipment is, to tell which one
+	// holds the orders up.
+	GetEquipmentStats(ctx context.Context, in *GetEquipmentStatsRequest, opts ...grpc.CallOption) (*GetEquipmentStatsResponse, error)
+}
+
+type cafeAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCafeAdminServiceClient(cc grpc.ClientConnInterface) CafeAdminServiceClient {
+	return &cafeAdminServiceClient{cc}
+}
+
+func (c *cafeAdminServiceClient) ResizeEquipment(ctx context.Context, in *ResizeEquipmentRequest, opts ...grpc.CallOption) (*ResizeEquipmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResizeEquipmentResponse)
+	err := c.cc.Invoke(ctx, CafeAdminService_ResizeEquipment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cafeAdminServiceClient) Restock(ctx context.Context, in *RestockRequest, opts ...grpc.CallOption) (*RestockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestockResponse)
+	err := c.cc.Invoke(ctx, CafeAdminService_Restock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cafeAdminServiceClient) GetInventory(ctx context.Context, in *GetInventoryRequest, opts ...grpc.CallOption) (*GetInventoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInventoryResponse)
+	err := c.cc.Invoke(ctx, CafeAdminService_GetInventory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cafeAdminServiceClient) GetEquipmentStats(ctx context.Context, in *GetEquipmentStatsRequest, opts ...grpc.CallOption) (*GetEquipmentStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEquipmentStatsResponse)
+	err := c.cc.Invoke(ctx, CafeAdminService_GetEquipmentStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CafeAdminServiceServer is the server API for CafeAdminService service.
+// All implementations must embed UnimplementedCafeAdminServiceServer
+// for forward compatibility.
+//
+// CafeAdminService operates the cafe while it is running.
+type CafeAdminServiceServer interface {
+	// ResizeEquipment sets the number of workers of an equipment. Workers taken
+	// away finish the step they are on first.
+	ResizeEquipment(context.Context, *ResizeEquipmentRequest) (*ResizeEquipmentResponse, error)
+	// Restock adds to the stock of an ingredient of the inventory.
+	Restock(context.Context, *RestockRequest) (*RestockResponse, error)
+	// GetInventory returns the stock of every ingredient.
+	GetInventory(context.Context, *GetInventoryRequest) (*GetInventoryResponse, error)
+	// GetEquipmentStats returns how busy every equipment is, to tell which one
+	// holds the orders up.
+	GetEquipmentStats(context.Context, *GetEquipmentStatsRequest) (*GetEquipmentStatsResponse, error)
+	mustEmbedUnimplementedCafeAdminServiceServer()
+}
+
+// UnimplementedCafeAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCafeAdminServiceServer struct{}
+
+func (UnimplementedCafeAdminServiceServer) ResizeEquipment(context.Context, *ResizeEquipmentRequest) (*ResizeEquipmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResizeEquipment not implemented")
+}
+func (UnimplementedCafeAdminServiceServer) Restock(context.Context, *RestockRequest) (*RestockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restock not implemented")
+}
+func (UnimplementedCafeAdminServiceServer) GetInventory(context.Context, *GetInventoryRequest) (*GetInventoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInventory not implemented")
+}
+func (UnimplementedCafeAdminServiceServer) GetEquipmentStats(context.Context, *GetEquipmentStatsRequest) (*GetEquipmentStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEquipmentStats not implemented")
+}
+func (UnimplementedCafeAdminServiceServer) mustEmbedUnimplementedCafeAdminServiceServer() {}
+func (UnimplementedCafeAdminServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeCafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CafeAdminServiceServer will
+// result in compilation errors.
+type UnsafeCafeAdminServiceServer interface {
+	mustEmbedUnimplementedCafeAdminServiceServer()
+}
+
+func RegisterCafeAdminServiceServer(s grpc.ServiceRegistrar, srv CafeAdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCafeAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CafeAdminService_ServiceDesc, srv)
+}
+
+func _CafeAdminService_ResizeEquipment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResizeEquipmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CafeAdminServiceServer).ResizeEquipment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CafeAdminService_ResizeEquipment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CafeAdminServiceServer).ResizeEquipment(ctx, req.(*ResizeEquipmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CafeAdminService_Restock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CafeAdminServiceServer).Restock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CafeAdminService_Restock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CafeAdminServiceServer).Restock(ctx, req.(*RestockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CafeAdminService_GetInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CafeAdminServiceServer).GetInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CafeAdminService_GetInventory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CafeAdminServiceServer).GetInventory(ctx, req.(*GetInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CafeAdminService_GetEquipmentStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEquipmentStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CafeAdminServiceServer).GetEquipmentStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CafeAdminService_GetEquipmentStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CafeAdminServiceServer).GetEquipmentStats(ctx, req.(*GetEquipmentStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CafeAdminService_ServiceDesc is the grpc.ServiceDesc for CafeAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CafeAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pkg.proto.cafe.v1.CafeAdminService",
+	HandlerType: (*CafeAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ResizeEquipment",
+			Handler:    _CafeAdminService_ResizeEquipment_Handler,
+		},
+		{
+			MethodName: "Restock",
+			Handler:    _CafeAdminService_Restock_Handler,
+		},
+		{
+			MethodName: "GetInventory",
+			Handler:    _CafeAdminService_GetInventory_Handler,
+		},
+		{
+			MethodName: "GetEquipmentStats",
+			Handler:    _CafeAdminService_GetEquipmentStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/proto/cafe/v1/admin.proto",
+}
@@ -0,0 +1,1733 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: pkg/proto/cafe/v1/cafe.proto
+
+package cafepb
+
+import (
+	v1 "github.com/rexyajaib/gopher-cafe/pkg/gen/go/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type BrewEventType int32
+
+const (
+	BrewEventType_BREW_EVENT_TYPE_UNSPECIFIED     BrewEventType = 0
+	BrewEventType_BREW_EVENT_TYPE_STEP_STARTED    BrewEventType = 1
+	BrewEventType_BREW_EVENT_TYPE_STEP_FINISHED   BrewEventType = 2
+	BrewEventType_BREW_EVENT_TYPE_ORDER_COMPLETED BrewEventType = 3
+	BrewEventType_BREW_EVENT_TYPE_ORDER_FAILED    BrewEventType = 4
+	BrewEventType_BREW_EVENT_TYPE_ORDER_STATE     BrewEventType = 5
+)
+
+// Enum value maps for BrewEventType.
+var (
+	BrewEventType_name = map[int32]string{
+		0: "BREW_EVENT_TYPE_UNSPECIFIED",
+		1: "BREW_EVENT_TYPE_STEP_STARTED",
+		2: "BREW_EVENT_TYPE_STEP_FINISHED",
+		3: "BREW_EVENT_TYPE_ORDER_COMPLETED",
+		4: "BREW_EVENT_TYPE_ORDER_FAILED",
+		5: "BREW_EVENT_TYPE_ORDER_STATE",
+	}
+	BrewEventType_value = map[string]int32{
+		"BREW_EVENT_TYPE_UNSPECIFIED":     0,
+		"BREW_EVENT_TYPE_STEP_STARTED":    1,
+		"BREW_EVENT_TYPE_STEP_FINISHED":   2,
+		"BREW_EVENT_TYPE_ORDER_COMPLETED": 3,
+		"BREW_EVENT_TYPE_ORDER_FAILED":    4,
+		"BREW_EVENT_TYPE_ORDER_STATE":     5,
+	}
+)
+
+func (x BrewEventType) Enum() *BrewEventType {
+	p := new(BrewEventType)
+	*p = x
+	return p
+}
+
+func (x BrewEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BrewEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_cafe_v1_cafe_proto_enumTypes[0].Descriptor()
+}
+
+func (BrewEventType) Type() protoreflect.EnumType {
+	return &file_pkg_proto_cafe_v1_cafe_proto_enumTypes[0]
+}
+
+func (x BrewEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BrewEventType.Descriptor instead.
+func (BrewEventType) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{0}
+}
+
+type OrderStatus int32
+
+const (
+	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
+	OrderStatus_ORDER_STATUS_COMPLETED   OrderStatus = 1
+	OrderStatus_ORDER_STATUS_FAILED      OrderStatus = 2
+	OrderStatus_ORDER_STATUS_CANCELLED   OrderStatus = 3
+)
+
+// Enum value maps for OrderStatus.
+var (
+	OrderStatus_name = map[int32]string{
+		0: "ORDER_STATUS_UNSPECIFIED",
+		1: "ORDER_STATUS_COMPLETED",
+		2: "ORDER_STATUS_FAILED",
+		3: "ORDER_STATUS_CANCELLED",
+	}
+	OrderStatus_value = map[string]int32{
+		"ORDER_STATUS_UNSPECIFIED": 0,
+		"ORDER_STATUS_COMPLETED":   1,
+		"ORDER_STATUS_FAILED":      2,
+		"ORDER_STATUS_CANCELLED":   3,
+	}
+)
+
+func (x OrderStatus) Enum() *OrderStatus {
+	p := new(OrderStatus)
+	*p = x
+	return p
+}
+
+func (x OrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_cafe_v1_cafe_proto_enumTypes[1].Descriptor()
+}
+
+func (OrderStatus) Type() protoreflect.EnumType {
+	return &file_pkg_proto_cafe_v1_cafe_proto_enumTypes[1]
+}
+
+func (x OrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderStatus.Descriptor instead.
+func (OrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{1}
+}
+
+// OrderState is where an order stands in its lifecycle: received, queued,
+// brewing its steps, ready, then picked up, unless cancelled or failed on the
+// way.
+type OrderState int32
+
+const (
+	OrderState_ORDER_STATE_UNSPECIFIED OrderState = 0
+	OrderState_ORDER_STATE_RECEIVED    OrderState = 1
+	OrderState_ORDER_STATE_QUEUED      OrderState = 2
+	OrderState_ORDER_STATE_BREWING     OrderState = 3
+	OrderState_ORDER_STATE_READY       OrderState = 4
+	OrderState_ORDER_STATE_PICKED_UP   OrderState = 5
+	OrderState_ORDER_STATE_CANCELLED   OrderState = 6
+	OrderState_ORDER_STATE_FAILED      OrderState = 7
+)
+
+// Enum value maps for OrderState.
+var (
+	OrderState_name = map[int32]string{
+		0: "ORDER_STATE_UNSPECIFIED",
+		1: "ORDER_STATE_RECEIVED",
+		2: "ORDER_STATE_QUEUED",
+		3: "ORDER_STATE_BREWING",
+		4: "ORDER_STATE_READY",
+		5: "ORDER_STATE_PICKED_UP",
+		6: "ORDER_STATE_CANCELLED",
+		7: "ORDER_STATE_FAILED",
+	}
+	OrderState_value = map[string]int32{
+		"ORDER_STATE_UNSPECIFIED": 0,
+		"ORDER_STATE_RECEIVED":    1,
+		"ORDER_STATE_QUEUED":      2,
+		"ORDER_STATE_BREWING":     3,
+		"ORDER_STATE_READY":       4,
+		"ORDER_STATE_PICKED_UP":   5,
+		"ORDER_STATE_CANCELLED":   6,
+		"ORDER_STATE_FAILED":      7,
+	}
+)
+
+func (x OrderState) Enum() *OrderState {
+	p := new(OrderState)
+	*p = x
+	return p
+}
+
+func (x OrderState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderState) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_cafe_v1_cafe_proto_enumTypes[2].Descriptor()
+}
+
+func (OrderState) Type() protoreflect.EnumType {
+	return &file_pkg_proto_cafe_v1_cafe_proto_enumTypes[2]
+}
+
+func (x OrderState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderState.Descriptor instead.
+func (OrderState) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{2}
+}
+
+type Size int32
+
+const (
+	// SIZE_UNSPECIFIED is a medium.
+	Size_SIZE_UNSPECIFIED Size = 0
+	Size_SIZE_SMALL       Size = 1
+	Size_SIZE_MEDIUM      Size = 2
+	Size_SIZE_LARGE       Size = 3
+)
+
+// Enum value maps for Size.
+var (
+	Size_name = map[int32]string{
+		0: "SIZE_UNSPECIFIED",
+		1: "SIZE_SMALL",
+		2: "SIZE_MEDIUM",
+		3: "SIZE_LARGE",
+	}
+	Size_value = map[string]int32{
+		"SIZE_UNSPECIFIED": 0,
+		"SIZE_SMALL":       1,
+		"SIZE_MEDIUM":      2,
+		"SIZE_LARGE":       3,
+	}
+)
+
+func (x Size) Enum() *Size {
+	p := new(Size)
+	*p = x
+	return p
+}
+
+func (x Size) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Size) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_cafe_v1_cafe_proto_enumTypes[3].Descriptor()
+}
+
+func (Size) Type() protoreflect.EnumType {
+	return &file_pkg_proto_cafe_v1_cafe_proto_enumTypes[3]
+}
+
+func (x Size) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Size.Descriptor instead.
+func (Size) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{3}
+}
+
+type Milk int32
+
+const (
+	// MILK_UNSPECIFIED keeps the milk of the recipe.
+	Milk_MILK_UNSPECIFIED Milk = 0
+	Milk_MILK_OAT         Milk = 1
+	Milk_MILK_SOY         Milk = 2
+	Milk_MILK_ALMOND      Milk = 3
+)
+
+// Enum value maps for Milk.
+var (
+	Milk_name = map[int32]string{
+		0: "MILK_UNSPECIFIED",
+		1: "MILK_OAT",
+		2: "MILK_SOY",
+		3: "MILK_ALMOND",
+	}
+	Milk_value = map[string]int32{
+		"MILK_UNSPECIFIED": 0,
+		"MILK_OAT":         1,
+		"MILK_SOY":         2,
+		"MILK_ALMOND":      3,
+	}
+)
+
+func (x Milk) Enum() *Milk {
+	p := new(Milk)
+	*p = x
+	return p
+}
+
+func (x Milk) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Milk) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_cafe_v1_cafe_proto_enumTypes[4].Descriptor()
+}
+
+func (Milk) Type() protoreflect.EnumType {
+	return &file_pkg_proto_cafe_v1_cafe_proto_enumTypes[4]
+}
+
+func (x Milk) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Milk.Descriptor instead.
+func (Milk) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{4}
+}
+
+// Priority is the class of an order. The equipment serves the steps of the
+// higher classes first, while a step that waited long enough goes ahead of the
+// higher classes so that none of them starves.
+type Priority int32
+
+const (
+	// PRIORITY_UNSPECIFIED is a walk-in.
+	Priority_PRIORITY_UNSPECIFIED Priority = 0
+	Priority_PRIORITY_MOBILE      Priority = 1
+	Priority_PRIORITY_VIP         Priority = 2
+)
+
+// Enum value maps for Priority.
+var (
+	Priority_name = map[int32]string{
+		0: "PRIORITY_UNSPECIFIED",
+		1: "PRIORITY_MOBILE",
+		2: "PRIORITY_VIP",
+	}
+	Priority_value = map[string]int32{
+		"PRIORITY_UNSPECIFIED": 0,
+		"PRIORITY_MOBILE":      1,
+		"PRIORITY_VIP":         2,
+	}
+)
+
+func (x Priority) Enum() *Priority {
+	p := new(Priority)
+	*p = x
+	return p
+}
+
+func (x Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_cafe_v1_cafe_proto_enumTypes[5].Descriptor()
+}
+
+func (Priority) Type() protoreflect.EnumType {
+	return &file_pkg_proto_cafe_v1_cafe_proto_enumTypes[5]
+}
+
+func (x Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Priority.Descriptor instead.
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{5}
+}
+
+type ExecuteBrewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Baristas      int32                  `protobuf:"varint,1,opt,name=baristas,proto3" json:"baristas,omitempty"`
+	Orders        []*Order               `protobuf:"bytes,2,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteBrewRequest) Reset() {
+	*x = ExecuteBrewRequest{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteBrewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteBrewRequest) ProtoMessage() {}
+
+func (x *ExecuteBrewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteBrewRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteBrewRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ExecuteBrewRequest) GetBaristas() int32 {
+	if x != nil {
+		return x.Baristas
+	}
+	return 0
+}
+
+func (x *ExecuteBrewRequest) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type Order struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Drink v1.DrinkType           `protobuf:"varint,2,opt,name=drink,proto3,enum=pkg.proto.v1.DrinkType" json:"drink,omitempty"`
+	// drink_name orders a drink of the menu by name, e.g. "latte", and takes
+	// precedence over drink. It reaches the drinks without a DrinkType value.
+	DrinkName string     `protobuf:"bytes,3,opt,name=drink_name,json=drinkName,proto3" json:"drink_name,omitempty"`
+	Modifiers *Modifiers `protobuf:"bytes,4,opt,name=modifiers,proto3" json:"modifiers,omitempty"`
+	// due_at_ms is the Unix time in milliseconds the order is promised for,
+	// e.g. the pickup time of a mobile order. Orders without one have no
+	// deadline. The "edf" scheduler brews the earliest due orders first.
+	DueAtMs       int64    `protobuf:"varint,5,opt,name=due_at_ms,json=dueAtMs,proto3" json:"due_at_ms,omitempty"`
+	Priority      Priority `protobuf:"varint,6,opt,name=priority,proto3,enum=pkg.proto.cafe.v1.Priority" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Order) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Order) GetDrink() v1.DrinkType {
+	if x != nil {
+		return x.Drink
+	}
+	return v1.DrinkType(0)
+}
+
+func (x *Order) GetDrinkName() string {
+	if x != nil {
+		return x.DrinkName
+	}
+	return ""
+}
+
+func (x *Order) GetModifiers() *Modifiers {
+	if x != nil {
+		return x.Modifiers
+	}
+	return nil
+}
+
+func (x *Order) GetDueAtMs() int64 {
+	if x != nil {
+		return x.DueAtMs
+	}
+	return 0
+}
+
+func (x *Order) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+// Modifiers customize the recipe of a drink. A small or a large scales the
+// step durations and the ingredients of the recipe, an extra shot pulls one
+// more espresso, milk replaces the milk of the recipe and iced serves the
+// drink over ice instead of steaming its milk.
+type Modifiers struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Size  Size                   `protobuf:"varint,1,opt,name=size,proto3,enum=pkg.proto.cafe.v1.Size" json:"size,omitempty"`
+	// extra_shots must be between 0 and 3.
+	ExtraShots    uint32 `protobuf:"varint,2,opt,name=extra_shots,json=extraShots,proto3" json:"extra_shots,omitempty"`
+	Milk          Milk   `protobuf:"varint,3,opt,name=milk,proto3,enum=pkg.proto.cafe.v1.Milk" json:"milk,omitempty"`
+	Iced          bool   `protobuf:"varint,4,opt,name=iced,proto3" json:"iced,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Modifiers) Reset() {
+	*x = Modifiers{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Modifiers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Modifiers) ProtoMessage() {}
+
+func (x *Modifiers) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Modifiers.ProtoReflect.Descriptor instead.
+func (*Modifiers) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Modifiers) GetSize() Size {
+	if x != nil {
+		return x.Size
+	}
+	return Size_SIZE_UNSPECIFIED
+}
+
+func (x *Modifiers) GetExtraShots() uint32 {
+	if x != nil {
+		return x.ExtraShots
+	}
+	return 0
+}
+
+func (x *Modifiers) GetMilk() Milk {
+	if x != nil {
+		return x.Milk
+	}
+	return Milk_MILK_UNSPECIFIED
+}
+
+func (x *Modifiers) GetIced() bool {
+	if x != nil {
+		return x.Iced
+	}
+	return false
+}
+
+type ExecuteBrewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*Result              `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteBrewResponse) Reset() {
+	*x = ExecuteBrewResponse{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteBrewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteBrewResponse) ProtoMessage() {}
+
+func (x *ExecuteBrewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteBrewResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteBrewResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ExecuteBrewResponse) GetResults() []*Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Result of an order. Orders that did not complete have no steps and tell why
+// in error. Orders with a due time tell whether they met it, and lateness_ms
+// tells by how much a completed order was late, or early when negative.
+type Result struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Drink         v1.DrinkType           `protobuf:"varint,2,opt,name=drink,proto3,enum=pkg.proto.v1.DrinkType" json:"drink,omitempty"`
+	Steps         []*Step                `protobuf:"bytes,3,rep,name=steps,proto3" json:"steps,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,4,opt,name=status,proto3,enum=pkg.proto.cafe.v1.OrderStatus" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	DrinkName     string                 `protobuf:"bytes,6,opt,name=drink_name,json=drinkName,proto3" json:"drink_name,omitempty"`
+	DueAtMs       int64                  `protobuf:"varint,7,opt,name=due_at_ms,json=dueAtMs,proto3" json:"due_at_ms,omitempty"`
+	LatenessMs    int64                  `protobuf:"varint,8,opt,name=lateness_ms,json=latenessMs,proto3" json:"lateness_ms,omitempty"`
+	MetDeadline   bool                   `protobuf:"varint,9,opt,name=met_deadline,json=metDeadline,proto3" json:"met_deadline,omitempty"`
+	Priority      Priority               `protobuf:"varint,10,opt,name=priority,proto3,enum=pkg.proto.cafe.v1.Priority" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Result) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *Result) GetDrink() v1.DrinkType {
+	if x != nil {
+		return x.Drink
+	}
+	return v1.DrinkType(0)
+}
+
+func (x *Result) GetSteps() []*Step {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *Result) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *Result) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Result) GetDrinkName() string {
+	if x != nil {
+		return x.DrinkName
+	}
+	return ""
+}
+
+func (x *Result) GetDueAtMs() int64 {
+	if x != nil {
+		return x.DueAtMs
+	}
+	return 0
+}
+
+func (x *Result) GetLatenessMs() int64 {
+	if x != nil {
+		return x.LatenessMs
+	}
+	return 0
+}
+
+func (x *Result) GetMetDeadline() bool {
+	if x != nil {
+		return x.MetDeadline
+	}
+	return false
+}
+
+func (x *Result) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+// Step splits the time between start_ms and end_ms into the time spent
+// waiting for a free worker (queued_at_ms to acquired_at_ms) and the time the
+// worker spent on it (acquired_at_ms to released_at_ms).
+type Step struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Equipment     v1.EquipmentType       `protobuf:"varint,1,opt,name=equipment,proto3,enum=pkg.proto.v1.EquipmentType" json:"equipment,omitempty"`
+	StartMs       int64                  `protobuf:"varint,2,opt,name=start_ms,json=startMs,proto3" json:"start_ms,omitempty"`
+	EndMs         int64                  `protobuf:"varint,3,opt,name=end_ms,json=endMs,proto3" json:"end_ms,omitempty"`
+	QueuedAtMs    int64                  `protobuf:"varint,4,opt,name=queued_at_ms,json=queuedAtMs,proto3" json:"queued_at_ms,omitempty"`
+	AcquiredAtMs  int64                  `protobuf:"varint,5,opt,name=acquired_at_ms,json=acquiredAtMs,proto3" json:"acquired_at_ms,omitempty"`
+	ReleasedAtMs  int64                  `protobuf:"varint,6,opt,name=released_at_ms,json=releasedAtMs,proto3" json:"released_at_ms,omitempty"`
+	WorkerId      int32                  `protobuf:"varint,7,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Step) Reset() {
+	*x = Step{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Step) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Step) ProtoMessage() {}
+
+func (x *Step) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Step.ProtoReflect.Descriptor instead.
+func (*Step) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Step) GetEquipment() v1.EquipmentType {
+	if x != nil {
+		return x.Equipment
+	}
+	return v1.EquipmentType(0)
+}
+
+func (x *Step) GetStartMs() int64 {
+	if x != nil {
+		return x.StartMs
+	}
+	return 0
+}
+
+func (x *Step) GetEndMs() int64 {
+	if x != nil {
+		return x.EndMs
+	}
+	return 0
+}
+
+func (x *Step) GetQueuedAtMs() int64 {
+	if x != nil {
+		return x.QueuedAtMs
+	}
+	return 0
+}
+
+func (x *Step) GetAcquiredAtMs() int64 {
+	if x != nil {
+		return x.AcquiredAtMs
+	}
+	return 0
+}
+
+func (x *Step) GetReleasedAtMs() int64 {
+	if x != nil {
+		return x.ReleasedAtMs
+	}
+	return 0
+}
+
+func (x *Step) GetWorkerId() int32 {
+	if x != nil {
+		return x.WorkerId
+	}
+	return 0
+}
+
+// BrewEvent carries the step for step events, with only equipment and
+// start_ms set until the step is finished, the result for order events and
+// the transition for state events.
+type BrewEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          BrewEventType          `protobuf:"varint,1,opt,name=type,proto3,enum=pkg.proto.cafe.v1.BrewEventType" json:"type,omitempty"`
+	OrderId       int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	TimeMs        int64                  `protobuf:"varint,3,opt,name=time_ms,json=timeMs,proto3" json:"time_ms,omitempty"`
+	StepIndex     int32                  `protobuf:"varint,4,opt,name=step_index,json=stepIndex,proto3" json:"step_index,omitempty"`
+	Step          *Step                  `protobuf:"bytes,5,opt,name=step,proto3" json:"step,omitempty"`
+	Result        *Result                `protobuf:"bytes,6,opt,name=result,proto3" json:"result,omitempty"`
+	Transition    *Transition            `protobuf:"bytes,7,opt,name=transition,proto3" json:"transition,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BrewEvent) Reset() {
+	*x = BrewEvent{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BrewEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BrewEvent) ProtoMessage() {}
+
+func (x *BrewEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BrewEvent.ProtoReflect.Descriptor instead.
+func (*BrewEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BrewEvent) GetType() BrewEventType {
+	if x != nil {
+		return x.Type
+	}
+	return BrewEventType_BREW_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *BrewEvent) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *BrewEvent) GetTimeMs() int64 {
+	if x != nil {
+		return x.TimeMs
+	}
+	return 0
+}
+
+func (x *BrewEvent) GetStepIndex() int32 {
+	if x != nil {
+		return x.StepIndex
+	}
+	return 0
+}
+
+func (x *BrewEvent) GetStep() *Step {
+	if x != nil {
+		return x.Step
+	}
+	return nil
+}
+
+func (x *BrewEvent) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BrewEvent) GetTransition() *Transition {
+	if x != nil {
+		return x.Transition
+	}
+	return nil
+}
+
+// Transition is a change of state of an order. step is the recipe index of
+// the step that started when the order goes to brewing.
+type Transition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          OrderState             `protobuf:"varint,1,opt,name=from,proto3,enum=pkg.proto.cafe.v1.OrderState" json:"from,omitempty"`
+	To            OrderState             `protobuf:"varint,2,opt,name=to,proto3,enum=pkg.proto.cafe.v1.OrderState" json:"to,omitempty"`
+	Step          int32                  `protobuf:"varint,3,opt,name=step,proto3" json:"step,omitempty"`
+	AtMs          int64                  `protobuf:"varint,4,opt,name=at_ms,json=atMs,proto3" json:"at_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transition) Reset() {
+	*x = Transition{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transition) ProtoMessage() {}
+
+func (x *Transition) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transition.ProtoReflect.Descriptor instead.
+func (*Transition) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Transition) GetFrom() OrderState {
+	if x != nil {
+		return x.From
+	}
+	return OrderState_ORDER_STATE_UNSPECIFIED
+}
+
+func (x *Transition) GetTo() OrderState {
+	if x != nil {
+		return x.To
+	}
+	return OrderState_ORDER_STATE_UNSPECIFIED
+}
+
+func (x *Transition) GetStep() int32 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *Transition) GetAtMs() int64 {
+	if x != nil {
+		return x.AtMs
+	}
+	return 0
+}
+
+type SubmitOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TicketId      string                 `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitOrdersResponse) Reset() {
+	*x = SubmitOrdersResponse{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitOrdersResponse) ProtoMessage() {}
+
+func (x *SubmitOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitOrdersResponse.ProtoReflect.Descriptor instead.
+func (*SubmitOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SubmitOrdersResponse) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+type GetOrderStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TicketId      string                 `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderStatusRequest) Reset() {
+	*x = GetOrderStatusRequest{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderStatusRequest) ProtoMessage() {}
+
+func (x *GetOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetOrderStatusRequest) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+type GetOrderStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticket        *Ticket                `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderStatusResponse) Reset() {
+	*x = GetOrderStatusResponse{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderStatusResponse) ProtoMessage() {}
+
+func (x *GetOrderStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderStatusResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetOrderStatusResponse) GetTicket() *Ticket {
+	if x != nil {
+		return x.Ticket
+	}
+	return nil
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TicketId      string                 `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CancelOrderRequest) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticket        *Ticket                `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CancelOrderResponse) GetTicket() *Ticket {
+	if x != nil {
+		return x.Ticket
+	}
+	return nil
+}
+
+type PickUpOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TicketId      string                 `protobuf:"bytes,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PickUpOrderRequest) Reset() {
+	*x = PickUpOrderRequest{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PickUpOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PickUpOrderRequest) ProtoMessage() {}
+
+func (x *PickUpOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PickUpOrderRequest.ProtoReflect.Descriptor instead.
+func (*PickUpOrderRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PickUpOrderRequest) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+type PickUpOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticket        *Ticket                `protobuf:"bytes,1,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PickUpOrderResponse) Reset() {
+	*x = PickUpOrderResponse{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PickUpOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PickUpOrderResponse) ProtoMessage() {}
+
+func (x *PickUpOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PickUpOrderResponse.ProtoReflect.Descriptor instead.
+func (*PickUpOrderResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PickUpOrderResponse) GetTicket() *Ticket {
+	if x != nil {
+		return x.Ticket
+	}
+	return nil
+}
+
+// Ticket follows the orders of a SubmitOrders request. Tickets are kept for a
+// while after they are done.
+type Ticket struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAtMs int64                  `protobuf:"varint,2,opt,name=created_at_ms,json=createdAtMs,proto3" json:"created_at_ms,omitempty"`
+	Orders      []*OrderProgress       `protobuf:"bytes,3,rep,name=orders,proto3" json:"orders,omitempty"`
+	// done tells whether every order is brewed, ready or final.
+	Done          bool `protobuf:"varint,4,opt,name=done,proto3" json:"done,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ticket) Reset() {
+	*x = Ticket{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ticket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ticket) ProtoMessage() {}
+
+func (x *Ticket) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ticket.ProtoReflect.Descriptor instead.
+func (*Ticket) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Ticket) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Ticket) GetCreatedAtMs() int64 {
+	if x != nil {
+		return x.CreatedAtMs
+	}
+	return 0
+}
+
+func (x *Ticket) GetOrders() []*OrderProgress {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *Ticket) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+// OrderProgress tells how far an order got. current_steps are the steps of a
+// brewing order that started and did not finish yet, with only equipment and
+// start_ms set, and result is set once the order is brewed. transitions are
+// every state the order went through, the first one being received.
+type OrderProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	DrinkName     string                 `protobuf:"bytes,2,opt,name=drink_name,json=drinkName,proto3" json:"drink_name,omitempty"`
+	State         OrderState             `protobuf:"varint,3,opt,name=state,proto3,enum=pkg.proto.cafe.v1.OrderState" json:"state,omitempty"`
+	CurrentSteps  []*Step                `protobuf:"bytes,4,rep,name=current_steps,json=currentSteps,proto3" json:"current_steps,omitempty"`
+	StepsDone     int32                  `protobuf:"varint,5,opt,name=steps_done,json=stepsDone,proto3" json:"steps_done,omitempty"`
+	StepsTotal    int32                  `protobuf:"varint,6,opt,name=steps_total,json=stepsTotal,proto3" json:"steps_total,omitempty"`
+	Result        *Result                `protobuf:"bytes,7,opt,name=result,proto3" json:"result,omitempty"`
+	Transitions   []*Transition          `protobuf:"bytes,8,rep,name=transitions,proto3" json:"transitions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderProgress) Reset() {
+	*x = OrderProgress{}
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderProgress) ProtoMessage() {}
+
+func (x *OrderProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cafe_v1_cafe_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderProgress.ProtoReflect.Descriptor instead.
+func (*OrderProgress) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *OrderProgress) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *OrderProgress) GetDrinkName() string {
+	if x != nil {
+		return x.DrinkName
+	}
+	return ""
+}
+
+func (x *OrderProgress) GetState() OrderState {
+	if x != nil {
+		return x.State
+	}
+	return OrderState_ORDER_STATE_UNSPECIFIED
+}
+
+func (x *OrderProgress) GetCurrentSteps() []*Step {
+	if x != nil {
+		return x.CurrentSteps
+	}
+	return nil
+}
+
+func (x *OrderProgress) GetStepsDone() int32 {
+	if x != nil {
+		return x.StepsDone
+	}
+	return 0
+}
+
+func (x *OrderProgress) GetStepsTotal() int32 {
+	if x != nil {
+		return x.StepsTotal
+	}
+	return 0
+}
+
+func (x *OrderProgress) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *OrderProgress) GetTransitions() []*Transition {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
+}
+
+var File_pkg_proto_cafe_v1_cafe_proto protoreflect.FileDescriptor
+
+const file_pkg_proto_cafe_v1_cafe_proto_rawDesc = "" +
+	"\n" +
+	"\x1cpkg/proto/cafe/v1/cafe.proto\x12\x11pkg.proto.cafe.v1\x1a\x1apkg/proto/v1/message.proto\"b\n" +
+	"\x12ExecuteBrewRequest\x12\x1a\n" +
+	"\bbaristas\x18\x01 \x01(\x05R\bbaristas\x120\n" +
+	"\x06orders\x18\x02 \x03(\v2\x18.pkg.proto.cafe.v1.OrderR\x06orders\"\xf6\x01\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12-\n" +
+	"\x05drink\x18\x02 \x01(\x0e2\x17.pkg.proto.v1.DrinkTypeR\x05drink\x12\x1d\n" +
+	"\n" +
+	"drink_name\x18\x03 \x01(\tR\tdrinkName\x12:\n" +
+	"\tmodifiers\x18\x04 \x01(\v2\x1c.pkg.proto.cafe.v1.ModifiersR\tmodifiers\x12\x1a\n" +
+	"\tdue_at_ms\x18\x05 \x01(\x03R\adueAtMs\x127\n" +
+	"\bpriority\x18\x06 \x01(\x0e2\x1b.pkg.proto.cafe.v1.PriorityR\bpriority\"\x9a\x01\n" +
+	"\tModifiers\x12+\n" +
+	"\x04size\x18\x01 \x01(\x0e2\x17.pkg.proto.cafe.v1.SizeR\x04size\x12\x1f\n" +
+	"\vextra_shots\x18\x02 \x01(\rR\n" +
+	"extraShots\x12+\n" +
+	"\x04milk\x18\x03 \x01(\x0e2\x17.pkg.proto.cafe.v1.MilkR\x04milk\x12\x12\n" +
+	"\x04iced\x18\x04 \x01(\bR\x04iced\"J\n" +
+	"\x13ExecuteBrewResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.pkg.proto.cafe.v1.ResultR\aresults\"\x87\x03\n" +
+	"\x06Result\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12-\n" +
+	"\x05drink\x18\x02 \x01(\x0e2\x17.pkg.proto.v1.DrinkTypeR\x05drink\x12-\n" +
+	"\x05steps\x18\x03 \x03(\v2\x17.pkg.proto.cafe.v1.StepR\x05steps\x126\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1e.pkg.proto.cafe.v1.OrderStatusR\x06status\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"drink_name\x18\x06 \x01(\tR\tdrinkName\x12\x1a\n" +
+	"\tdue_at_ms\x18\a \x01(\x03R\adueAtMs\x12\x1f\n" +
+	"\vlateness_ms\x18\b \x01(\x03R\n" +
+	"latenessMs\x12!\n" +
+	"\fmet_deadline\x18\t \x01(\bR\vmetDeadline\x127\n" +
+	"\bpriority\x18\n" +
+	" \x01(\x0e2\x1b.pkg.proto.cafe.v1.PriorityR\bpriority\"\xfe\x01\n" +
+	"\x04Step\x129\n" +
+	"\tequipment\x18\x01 \x01(\x0e2\x1b.pkg.proto.v1.EquipmentTypeR\tequipment\x12\x19\n" +
+	"\bstart_ms\x18\x02 \x01(\x03R\astartMs\x12\x15\n" +
+	"\x06end_ms\x18\x03 \x01(\x03R\x05endMs\x12 \n" +
+	"\fqueued_at_ms\x18\x04 \x01(\x03R\n" +
+	"queuedAtMs\x12$\n" +
+	"\x0eacquired_at_ms\x18\x05 \x01(\x03R\facquiredAtMs\x12$\n" +
+	"\x0ereleased_at_ms\x18\x06 \x01(\x03R\freleasedAtMs\x12\x1b\n" +
+	"\tworker_id\x18\a \x01(\x05R\bworkerId\"\xb3\x02\n" +
+	"\tBrewEvent\x124\n" +
+	"\x04type\x18\x01 \x01(\x0e2 .pkg.proto.cafe.v1.BrewEventTypeR\x04type\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\x12\x17\n" +
+	"\atime_ms\x18\x03 \x01(\x03R\x06timeMs\x12\x1d\n" +
+	"\n" +
+	"step_index\x18\x04 \x01(\x05R\tstepIndex\x12+\n" +
+	"\x04step\x18\x05 \x01(\v2\x17.pkg.proto.cafe.v1.StepR\x04step\x121\n" +
+	"\x06result\x18\x06 \x01(\v2\x19.pkg.proto.cafe.v1.ResultR\x06result\x12=\n" +
+	"\n" +
+	"transition\x18\a \x01(\v2\x1d.pkg.proto.cafe.v1.TransitionR\n" +
+	"transition\"\x97\x01\n" +
+	"\n" +
+	"Transition\x121\n" +
+	"\x04from\x18\x01 \x01(\x0e2\x1d.pkg.proto.cafe.v1.OrderStateR\x04from\x12-\n" +
+	"\x02to\x18\x02 \x01(\x0e2\x1d.pkg.proto.cafe.v1.OrderStateR\x02to\x12\x12\n" +
+	"\x04step\x18\x03 \x01(\x05R\x04step\x12\x13\n" +
+	"\x05at_ms\x18\x04 \x01(\x03R\x04atMs\"3\n" +
+	"\x14SubmitOrdersResponse\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\tR\bticketId\"4\n" +
+	"\x15GetOrderStatusRequest\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\tR\bticketId\"K\n" +
+	"\x16GetOrderStatusResponse\x121\n" +
+	"\x06ticket\x18\x01 \x01(\v2\x19.pkg.proto.cafe.v1.TicketR\x06ticket\"1\n" +
+	"\x12CancelOrderRequest\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\tR\bticketId\"H\n" +
+	"\x13CancelOrderResponse\x121\n" +
+	"\x06ticket\x18\x01 \x01(\v2\x19.pkg.proto.cafe.v1.TicketR\x06ticket\"1\n" +
+	"\x12PickUpOrderRequest\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\tR\bticketId\"H\n" +
+	"\x13PickUpOrderResponse\x121\n" +
+	"\x06ticket\x18\x01 \x01(\v2\x19.pkg.proto.cafe.v1.TicketR\x06ticket\"\x8a\x01\n" +
+	"\x06Ticket\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
+	"\rcreated_at_ms\x18\x02 \x01(\x03R\vcreatedAtMs\x128\n" +
+	"\x06orders\x18\x03 \x03(\v2 .pkg.proto.cafe.v1.OrderProgressR\x06orders\x12\x12\n" +
+	"\x04done\x18\x04 \x01(\bR\x04done\"\xf0\x02\n" +
+	"\rOrderProgress\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x1d\n" +
+	"\n" +
+	"drink_name\x18\x02 \x01(\tR\tdrinkName\x123\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x1d.pkg.proto.cafe.v1.OrderStateR\x05state\x12<\n" +
+	"\rcurrent_steps\x18\x04 \x03(\v2\x17.pkg.proto.cafe.v1.StepR\fcurrentSteps\x12\x1d\n" +
+	"\n" +
+	"steps_done\x18\x05 \x01(\x05R\tstepsDone\x12\x1f\n" +
+	"\vsteps_total\x18\x06 \x01(\x05R\n" +
+	"stepsTotal\x121\n" +
+	"\x06result\x18\a \x01(\v2\x19.pkg.proto.cafe.v1.ResultR\x06result\x12?\n" +
+	"\vtransitions\x18\b \x03(\v2\x1d.pkg.proto.cafe.v1.TransitionR\vtransitions*\xdd\x01\n" +
+	"\rBrewEventType\x12\x1f\n" +
+	"\x1bBREW_EVENT_TYPE_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cBREW_EVENT_TYPE_STEP_STARTED\x10\x01\x12!\n" +
+	"\x1dBREW_EVENT_TYPE_STEP_FINISHED\x10\x02\x12#\n" +
+	"\x1fBREW_EVENT_TYPE_ORDER_COMPLETED\x10\x03\x12 \n" +
+	"\x1cBREW_EVENT_TYPE_ORDER_FAILED\x10\x04\x12\x1f\n" +
+	"\x1bBREW_EVENT_TYPE_ORDER_STATE\x10\x05*|\n" +
+	"\vOrderStatus\x12\x1c\n" +
+	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16ORDER_STATUS_COMPLETED\x10\x01\x12\x17\n" +
+	"\x13ORDER_STATUS_FAILED\x10\x02\x12\x1a\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x03*\xd9\x01\n" +
+	"\n" +
+	"OrderState\x12\x1b\n" +
+	"\x17ORDER_STATE_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14ORDER_STATE_RECEIVED\x10\x01\x12\x16\n" +
+	"\x12ORDER_STATE_QUEUED\x10\x02\x12\x17\n" +
+	"\x13ORDER_STATE_BREWING\x10\x03\x12\x15\n" +
+	"\x11ORDER_STATE_READY\x10\x04\x12\x19\n" +
+	"\x15ORDER_STATE_PICKED_UP\x10\x05\x12\x19\n" +
+	"\x15ORDER_STATE_CANCELLED\x10\x06\x12\x16\n" +
+	"\x12ORDER_STATE_FAILED\x10\a*M\n" +
+	"\x04Size\x12\x14\n" +
+	"\x10SIZE_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"SIZE_SMALL\x10\x01\x12\x0f\n" +
+	"\vSIZE_MEDIUM\x10\x02\x12\x0e\n" +
+	"\n" +
+	"SIZE_LARGE\x10\x03*I\n" +
+	"\x04Milk\x12\x14\n" +
+	"\x10MILK_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bMILK_OAT\x10\x01\x12\f\n" +
+	"\bMILK_SOY\x10\x02\x12\x0f\n" +
+	"\vMILK_ALMOND\x10\x03*K\n" +
+	"\bPriority\x12\x18\n" +
+	"\x14PRIORITY_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fPRIORITY_MOBILE\x10\x01\x12\x10\n" +
+	"\fPRIORITY_VIP\x10\x022\xc3\x04\n" +
+	"\vCafeService\x12\\\n" +
+	"\vExecuteBrew\x12%.pkg.proto.cafe.v1.ExecuteBrewRequest\x1a&.pkg.proto.cafe.v1.ExecuteBrewResponse\x12S\n" +
+	"\n" +
+	"StreamBrew\x12%.pkg.proto.cafe.v1.ExecuteBrewRequest\x1a\x1c.pkg.proto.cafe.v1.BrewEvent0\x01\x12^\n" +
+	"\fSubmitOrders\x12%.pkg.proto.cafe.v1.ExecuteBrewRequest\x1a'.pkg.proto.cafe.v1.SubmitOrdersResponse\x12e\n" +
+	"\x0eGetOrderStatus\x12(.pkg.proto.cafe.v1.GetOrderStatusRequest\x1a).pkg.proto.cafe.v1.GetOrderStatusResponse\x12\\\n" +
+	"\vCancelOrder\x12%.pkg.proto.cafe.v1.CancelOrderRequest\x1a&.pkg.proto.cafe.v1.CancelOrderResponse\x12\\\n" +
+	"\vPickUpOrder\x12%.pkg.proto.cafe.v1.PickUpOrderRequest\x1a&.pkg.proto.cafe.v1.PickUpOrderResponseB'Z%gopher-cafe/pkg/gen/go/cafe/v1;cafepbb\x06proto3"
+
+var (
+	file_pkg_proto_cafe_v1_cafe_proto_rawDescOnce sync.Once
+	file_pkg_proto_cafe_v1_cafe_proto_rawDescData []byte
+)
+
+func file_pkg_proto_cafe_v1_cafe_proto_rawDescGZIP() []byte {
+	file_pkg_proto_cafe_v1_cafe_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_cafe_v1_cafe_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_proto_cafe_v1_cafe_proto_rawDesc), len(file_pkg_proto_cafe_v1_cafe_proto_rawDesc)))
+	})
+	return file_pkg_proto_cafe_v1_cafe_proto_rawDescData
+}
+
+var file_pkg_proto_cafe_v1_cafe_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_pkg_proto_cafe_v1_cafe_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_pkg_proto_cafe_v1_cafe_proto_goTypes = []any{
+	(BrewEventType)(0),             // 0: pkg.proto.cafe.v1.BrewEventType
+	(OrderStatus)(0),               // 1: pkg.proto.cafe.v1.OrderStatus
+	(OrderState)(0),                // 2: pkg.proto.cafe.v1.OrderState
+	(Size)(0),                      // 3: pkg.proto.cafe.v1.Size
+	(Milk)(0),                      // 4: pkg.proto.cafe.v1.Milk
+	(Priority)(0),                  // 5: pkg.proto.cafe.v1.Priority
+	(*ExecuteBrewRequest)(nil),     // 6: pkg.proto.cafe.v1.ExecuteBrewRequest
+	(*Order)(nil),                  // 7: pkg.proto.cafe.v1.Order
+	(*Modifiers)(nil),              // 8: pkg.proto.cafe.v1.Modifiers
+	(*ExecuteBrewResponse)(nil),    // 9: pkg.proto.cafe.v1.ExecuteBrewResponse
+	(*Result)(nil),                 // 10: pkg.proto.cafe.v1.Result
+	(*Step)(nil),                   // 11: pkg.proto.cafe.v1.Step
+	(*BrewEvent)(nil),              // 12: pkg.proto.cafe.v1.BrewEvent
+	(*Transition)(nil),             // 13: pkg.proto.cafe.v1.Transition
+	(*SubmitOrdersResponse)(nil),   // 14: pkg.proto.cafe.v1.SubmitOrdersResponse
+	(*GetOrderStatusRequest)(nil),  // 15: pkg.proto.cafe.v1.GetOrderStatusRequest
+	(*GetOrderStatusResponse)(nil), // 16: pkg.proto.cafe.v1.GetOrderStatusResponse
+	(*CancelOrderRequest)(nil),     // 17: pkg.proto.cafe.v1.CancelOrderRequest
+	(*CancelOrderResponse)(nil),    // 18: pkg.proto.cafe.v1.CancelOrderResponse
+	(*PickUpOrderRequest)(nil),     // 19: pkg.proto.cafe.v1.PickUpOrderRequest
+	(*PickUpOrderResponse)(nil),    // 20: pkg.proto.cafe.v1.PickUpOrderResponse
+	(*Ticket)(nil),                 // 21: pkg.proto.cafe.v1.Ticket
+	(*OrderProgress)(nil),          // 22: pkg.proto.cafe.v1.OrderProgress
+	(v1.DrinkType)(0),              // 23: pkg.proto.v1.DrinkType
+	(v1.EquipmentType)(0),          // 24: pkg.proto.v1.EquipmentType
+}
+var file_pkg_proto_cafe_v1_cafe_proto_depIdxs = []int32{
+	7,  // 0: pkg.proto.cafe.v1.ExecuteBrewRequest.orders:type_name -> pkg.proto.cafe.v1.Order
+	23, // 1: pkg.proto.cafe.v1.Order.drink:type_name -> pkg.proto.v1.DrinkType
+	8,  // 2: pkg.proto.cafe.v1.Order.modifiers:type_name -> pkg.proto.cafe.v1.Modifiers
+	5,  // 3: pkg.proto.cafe.v1.Order.priority:type_name -> pkg.proto.cafe.v1.Priority
+	3,  // 4: pkg.proto.cafe.v1.Modifiers.size:type_name -> pkg.proto.cafe.v1.Size
+	4,  // 5: pkg.proto.cafe.v1.Modifiers.milk:type_name -> pkg.proto.cafe.v1.Milk
+	10, // 6: pkg.proto.cafe.v1.ExecuteBrewResponse.results:type_name -> pkg.proto.cafe.v1.Result
+	23, // 7: pkg.proto.cafe.v1.Result.drink:type_name -> pkg.proto.v1.DrinkType
+	11, // 8: pkg.proto.cafe.v1.Result.steps:type_name -> pkg.proto.cafe.v1.Step
+	1,  // 9: pkg.proto.cafe.v1.Result.status:type_name -> pkg.proto.cafe.v1.OrderStatus
+	5,  // 10: pkg.proto.cafe.v1.Result.priority:type_name -> pkg.proto.cafe.v1.Priority
+	24, // 11: pkg.proto.cafe.v1.Step.equipment:type_name -> pkg.proto.v1.EquipmentType
+	0,  // 12: pkg.proto.cafe.v1.BrewEvent.type:type_name -> pkg.proto.cafe.v1.BrewEventType
+	11, // 13: pkg.proto.cafe.v1.BrewEvent.step:type_name -> pkg.proto.cafe.v1.Step
+	10, // 14: pkg.proto.cafe.v1.BrewEvent.result:type_name -> pkg.proto.cafe.v1.Result
+	13, // 15: pkg.proto.cafe.v1.BrewEvent.transition:type_name -> pkg.proto.cafe.v1.Transition
+	2,  // 16: pkg.proto.cafe.v1.Transition.from:type_name -> pkg.proto.cafe.v1.OrderState
+	2,  // 17: pkg.proto.cafe.v1.Transition.to:type_name -> pkg.proto.cafe.v1.OrderState
+	21, // 18: pkg.proto.cafe.v1.GetOrderStatusResponse.ticket:type_name -> pkg.proto.cafe.v1.Ticket
+	21, // 19: pkg.proto.cafe.v1.CancelOrderResponse.ticket:type_name -> pkg.proto.cafe.v1.Ticket
+	21, // 20: pkg.proto.cafe.v1.PickUpOrderResponse.ticket:type_name -> pkg.proto.cafe.v1.Ticket
+	22, // 21: pkg.proto.cafe.v1.Ticket.orders:type_name -> pkg.proto.cafe.v1.OrderProgress
+	2,  // 22: pkg.proto.cafe.v1.OrderProgress.state:type_name -> pkg.proto.cafe.v1.OrderState
+	11, // 23: pkg.proto.cafe.v1.OrderProgress.current_steps:type_name -> pkg.proto.cafe.v1.Step
+	10, // 24: pkg.proto.cafe.v1.OrderProgress.result:type_name -> pkg.proto.cafe.v1.Result
+	13, // 25: pkg.proto.cafe.v1.OrderProgress.transitions:type_name -> pkg.proto.cafe.v1.Transition
+	6,  // 26: pkg.proto.cafe.v1.CafeService.ExecuteBrew:input_type -> pkg.proto.cafe.v1.ExecuteBrewRequest
+	6,  // 27: pkg.proto.cafe.v1.CafeService.StreamBrew:input_type -> pkg.proto.cafe.v1.ExecuteBrewRequest
+	6,  // 28: pkg.proto.cafe.v1.CafeService.SubmitOrders:input_type -> pkg.proto.cafe.v1.ExecuteBrewRequest
+	15, // 29: pkg.proto.cafe.v1.CafeService.GetOrderStatus:input_type -> pkg.proto.cafe.v1.GetOrderStatusRequest
+	17, // 30: pkg.proto.cafe.v1.CafeService.CancelOrder:input_type -> pkg.proto.cafe.v1.CancelOrderRequest
+	19, // 31: pkg.proto.cafe.v1.CafeService.PickUpOrder:input_type -> pkg.proto.cafe.v1.PickUpOrderRequest
+	9,  // 32: pkg.proto.cafe.v1.CafeService.ExecuteBrew:output_type -> pkg.proto.cafe.v1.ExecuteBrewResponse
+	12, // 33: pkg.proto.cafe.v1.CafeService.StreamBrew:output_type -> pkg.proto.cafe.v1.BrewEvent
+	14, // 34: pkg.proto.cafe.v1.CafeService.SubmitOrders:output_type -> pkg.proto.cafe.v1.SubmitOrdersResponse
+	16, // 35: pkg.proto.cafe.v1.CafeService.GetOrderStatus:output_type -> pkg.proto.cafe.v1.GetOrderStatusResponse
+	18, // 36: pkg.proto.cafe.v1.CafeService.CancelOrder:output_type -> pkg.proto.cafe.v1.CancelOrderResponse
+	20, // 37: pkg.proto.cafe.v1.CafeService.PickUpOrder:output_type -> pkg.proto.cafe.v1.PickUpOrderResponse
+	32, // [32:38] is the sub-list for method output_type
+	26, // [26:32] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_cafe_v1_cafe_proto_init() }
+func file_pkg_proto_cafe_v1_cafe_proto_init() {
+	if File_pkg_proto_cafe_v1_cafe_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_proto_cafe_v1_cafe_proto_rawDesc), len(file_pkg_proto_cafe_v1_cafe_proto_rawDesc)),
+			NumEnums:      6,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_proto_cafe_v1_cafe_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_cafe_v1_cafe_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_cafe_v1_cafe_proto_enumTypes,
+		MessageInfos:      file_pkg_proto_cafe_v1_cafe_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_cafe_v1_cafe_proto = out.File
+	file_pkg_proto_cafe_v1_cafe_proto_goTypes = nil
+	file_pkg_proto_cafe_v1_cafe_proto_depIdxs = nil
+}
@@ -0,0 +1,335 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: pkg/proto/cafe/v1/cafe.proto
+
+package cafepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CafeService_ExecuteBrew_FullMethodName    = "/pkg.proto.cafe.v1.CafeService/ExecuteBrew"
+	CafeService_StreamBrew_FullMethodName     = "/pkg.proto.cafe.v1.CafeService/StreamBrew"
+	CafeService_SubmitOrders_FullMethodName   = "/pkg.proto.cafe.v1.CafeService/SubmitOrders"
+	CafeService_GetOrderStatus_FullMethodName = "/pkg.proto.cafe.v1.CafeService/GetOrderStatus"
+	CafeService_CancelOrder_FullMethodName    = "/pkg.proto.cafe.v1.CafeService/CancelOrder"
+	CafeService_PickUpOrder_FullMethodName    = "/pkg.proto.cafe.v1.CafeService/PickUpOrder"
+)
+
+// CafeServiceClient is the client API for CafeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CafeService extends pkg.proto.v1.GopherCafeService with the details that do
+// not fit its messages.
+type CafeServiceClient interface {
+	ExecuteBrew(ctx context.Context, in *ExecuteBrewRequest, opts ...grpc.CallOption) (*ExecuteBrewResponse, error)
+	// StreamBrew runs ExecuteBrew and streams its progress as it happens.
+	StreamBrew(ctx context.Context, in *ExecuteBrewRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BrewEvent], error)
+	// SubmitOrders checks the orders like ExecuteBrew and brews them in the
+	// background, returning a ticket right away to follow them with.
+	SubmitOrders(ctx context.Context, in *ExecuteBrewRequest, opts ...grpc.CallOption) (*SubmitOrdersResponse, error)
+	// GetOrderStatus tells how far the orders of a ticket got.
+	GetOrderStatus(ctx context.Context, in *GetOrderStatusRequest, opts ...grpc.CallOption) (*GetOrderStatusResponse, error)
+	// CancelOrder stops brewing the orders of a ticket that are not brewed yet.
+	// They turn cancelled shortly after, as the baristas give up on them.
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error)
+	// PickUpOrder hands over the ready orders of a ticket.
+	PickUpOrder(ctx context.Context, in *PickUpOrderRequest, opts ...grpc.CallOption) (*PickUpOrderResponse, error)
+}
+
+type cafeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCafeServiceClient(cc grpc.ClientConnInterface) CafeServiceClient {
+	return &cafeServiceClient{cc}
+}
+
+func (c *cafeServiceClient) ExecuteBrew(ctx context.Context, in *ExecuteBrewRequest, opts ...grpc.CallOption) (*ExecuteBrewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExecuteBrewResponse)
+	err := c.cc.Invoke(ctx, CafeService_ExecuteBrew_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cafeServiceClient) StreamBrew(ctx context.Context, in *ExecuteBrewRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BrewEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CafeService_ServiceDesc.Streams[0], CafeService_StreamBrew_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExecuteBrewRequest, BrewEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CafeService_StreamBrewClient = grpc.ServerStreamingClient[BrewEvent]
+
+func (c *cafeServiceClient) SubmitOrders(ctx context.Context, in *ExecuteBrewRequest, opts ...grpc.CallOption) (*SubmitOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitOrdersResponse)
+	err := c.cc.Invoke(ctx, CafeService_SubmitOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cafeServiceClient) GetOrderStatus(ctx context.Context, in *GetOrderStatusRequest, opts ...grpc.CallOption) (*GetOrderStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderStatusResponse)
+	err := c.cc.Invoke(ctx, CafeService_GetOrderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cafeServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelOrderResponse)
+	err := c.cc.Invoke(ctx, CafeService_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cafeServiceClient) PickUpOrder(ctx context.Context, in *PickUpOrderRequest, opts ...grpc.CallOption) (*PickUpOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PickUpOrderResponse)
+	err := c.cc.Invoke(ctx, CafeService_PickUpOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CafeServiceServer is the server API for CafeService service.
+// All implementations must embed UnimplementedCafeServiceServer
+// for forward compatibility.
+//
+// CafeService extends pkg.proto.v1.GopherCafeService with the details that do
+// not fit its messages.
+type CafeServiceServer interface {
+	ExecuteBrew(context.Context, *ExecuteBrewRequest) (*ExecuteBrewResponse, error)
+	// StreamBrew runs ExecuteBrew and streams its progress as it happens.
+	StreamBrew(*ExecuteBrewRequest, grpc.ServerStreamingServer[BrewEvent]) error
+	// SubmitOrders checks the orders like ExecuteBrew and brews them in the
+	// background, returning a ticket right away to follow them with.
+	SubmitOrders(context.Context, *ExecuteBrewRequest) (*SubmitOrdersResponse, error)
+	// GetOrderStatus tells how far the orders of a ticket got.
+	GetOrderStatus(context.Context, *GetOrderStatusRequest) (*GetOrderStatusResponse, error)
+	// CancelOrder stops brewing the orders of a ticket that are not brewed yet.
+	// They turn cancelled shortly after, as the baristas give up on them.
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
+	// PickUpOrder hands over the ready orders of a ticket.
+	PickUpOrder(context.Context, *PickUpOrderRequest) (*PickUpOrderResponse, error)
+	mustEmbedUnimplementedCafeServiceServer()
+}
+
+// UnimplementedCafeServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCafeServiceServer struct{}
+
+func (UnimplementedCafeServiceServer) ExecuteBrew(context.Context, *ExecuteBrewRequest) (*ExecuteBrewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteBrew not implemented")
+}
+func (UnimplementedCafeServiceServer) StreamBrew(*ExecuteBrewRequest, grpc.ServerStreamingServer[BrewEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBrew not implemented")
+}
+func (UnimplementedCafeServiceServer) SubmitOrders(context.Context, *ExecuteBrewRequest) (*SubmitOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitOrders not implemented")
+}
+func (UnimplementedCafeServiceServer) GetOrderStatus(context.Context, *GetOrderStatusRequest) (*GetOrderStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderStatus not implemented")
+}
+func (UnimplementedCafeServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedCafeServiceServer) PickUpOrder(context.Context, *PickUpOrderRequest) (*PickUpOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PickUpOrder not implemented")
+}
+func (UnimplementedCafeServiceServer) mustEmbedUnimplementedCafeServiceServer() {}
+func (UnimplementedCafeServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeCafeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CafeServiceServer will
+// result in compilation errors.
+type UnsafeCafeServiceServer interface {
+	mustEmbedUnimplementedCafeServiceServer()
+}
+
+func RegisterCafeServiceServer(s grpc.ServiceRegistrar, srv CafeServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCafeServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CafeService_ServiceDesc, srv)
+}
+
+func _CafeService_ExecuteBrew_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteBrewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CafeServiceServer).ExecuteBrew(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CafeService_ExecuteBrew_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CafeServiceServer).ExecuteBrew(ctx, req.(*ExecuteBrewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CafeService_StreamBrew_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecuteBrewRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CafeServiceServer).StreamBrew(m, &grpc.GenericServerStream[ExecuteBrewRequest, BrewEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CafeService_StreamBrewServer = grpc.ServerStreamingServer[BrewEvent]
+
+func _CafeService_SubmitOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteBrewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CafeServiceServer).SubmitOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CafeService_SubmitOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CafeServiceServer).SubmitOrders(ctx, req.(*ExecuteBrewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CafeService_GetOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CafeServiceServer).GetOrderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CafeService_GetOrderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CafeServiceServer).GetOrderStatus(ctx, req.(*GetOrderStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CafeService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CafeServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CafeService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CafeServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CafeService_PickUpOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PickUpOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CafeServiceServer).PickUpOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CafeService_PickUpOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CafeServiceServer).PickUpOrder(ctx, req.(*PickUpOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CafeService_ServiceDesc is the grpc.ServiceDesc for CafeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CafeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pkg.proto.cafe.v1.CafeService",
+	HandlerType: (*CafeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ExecuteBrew",
+			Handler:    _CafeService_ExecuteBrew_Handler,
+		},
+		{
+			MethodName: "SubmitOrders",
+			Handler:    _CafeService_SubmitOrders_Handler,
+		},
+		{
+			MethodName: "GetOrderStatus",
+			Handler:    _CafeService_GetOrderStatus_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _CafeService_CancelOrder_Handler,
+		},
+		{
+			MethodName: "PickUpOrder",
+			Handler:    _CafeService_PickUpOrder_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBrew",
+			Handler:       _CafeService_StreamBrew_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/proto/cafe/v1/cafe.proto",
+}